@@ -0,0 +1,72 @@
+package eventbus
+
+import (
+	"sync"
+)
+
+// pending struct, the queued work which is not delivered yet, Flush waits for it
+type pending struct {
+	mu   sync.Mutex
+	next uint64
+	jobs map[uint64]chan struct{}
+}
+
+// add registers a queued work, done must be called once after it is delivered
+func (p *pending) add() (done func()) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.jobs == nil {
+		p.jobs = make(map[uint64]chan struct{})
+	}
+	id, ch := p.next, make(chan struct{})
+	p.next++
+	p.jobs[id] = ch
+	return func() {
+		p.mu.Lock()
+		delete(p.jobs, id)
+		p.mu.Unlock()
+		close(ch)
+	}
+}
+
+// wait blocks until the work queued before the call is delivered
+func (p *pending) wait() {
+	p.mu.Lock()
+	jobs := make([]chan struct{}, 0, len(p.jobs))
+	for _, ch := range p.jobs {
+		jobs = append(jobs, ch)
+	}
+	p.mu.Unlock()
+	for _, ch := range jobs {
+		<-ch
+	}
+}
+
+// TriggerAsync - dispatch event on another goroutine, the returned channel is closed after all the handlers return.
+// The message is dropped after Shutdown and the returned channel is closed at once
+func (b *Bus[T]) TriggerAsync(topic string, msg ...T) <-chan struct{} {
+	done := make(chan struct{})
+	m := newMessage(topic, msg)
+	if b.closed.Load() {
+		b.dropMessage(m, DropClosed)
+		close(done)
+		return done
+	}
+	delivered := b.pending.add()
+	m.queued = true
+	go func() {
+		defer close(done)
+		defer delivered()
+		b.dispatch(b.Get(topic), m)
+	}()
+	return done
+}
+
+// Flush - block until the messages queued before the call are delivered, including the async triggers
+// and the messages held by Debounce which are dispatched immediately. The new messages are accepted meanwhile
+func (b *Bus[T]) Flush() {
+	for _, d := range b.debouncers.values() {
+		d.flush()
+	}
+	b.pending.wait()
+}
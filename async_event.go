@@ -0,0 +1,8 @@
+package eventbus
+
+// OnAsync - register topic event which is called on a dedicated goroutine, the messages are queued in a mailbox
+// of buffer messages, at least one, and Dispatch of the bus waits only while it is full. The goroutine exits and the queued
+// messages are dropped after the event is removed, use Flush to wait for the queued messages
+func (b *Bus[T]) OnAsync(topic string, buffer int, e Event[T]) *Bus[T] {
+	return b.onMailbox(topic, e, max(buffer, 1), false)
+}
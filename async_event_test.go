@@ -0,0 +1,43 @@
+package eventbus
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestOnAsync(t *testing.T) {
+	o := New[string]()
+	var calls int64
+	n := 0
+
+	slow := &sleepEvent{20 * time.Millisecond, &calls}
+	o.OnAsync("foo", 10, slow).On("foo", &N{&n, ""})
+
+	start := time.Now()
+	for i := 0; i < 5; i++ {
+		o.Trigger("foo", "test")
+	}
+	// the synchronous sibling isn't delayed by the slow event
+	if d := time.Since(start); d > 20*time.Millisecond {
+		t.Errorf("The triggers take %s", d)
+	}
+	if n != 5 {
+		t.Errorf("The counter is %d instead of being %d", n, 5)
+	}
+
+	o.Flush()
+	if c := atomic.LoadInt64(&calls); c != 5 {
+		t.Errorf("The counter is %d instead of being %d", c, 5)
+	}
+
+	// the queued messages are dropped after the event is removed
+	for i := 0; i < 5; i++ {
+		o.Trigger("foo", "test")
+	}
+	o.CleanSync()
+	o.Flush()
+	if c := atomic.LoadInt64(&calls); c > 6 {
+		t.Errorf("The counter is %d instead of being at most %d", c, 6)
+	}
+}
@@ -0,0 +1,252 @@
+package eventbus
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type sleepEvent struct {
+	d     time.Duration
+	calls *int64
+}
+
+func (e *sleepEvent) Dispatch(topic string, data ...string) {
+	time.Sleep(e.d)
+	atomic.AddInt64(e.calls, 1)
+}
+
+func TestTriggerAsync(t *testing.T) {
+	o := New[string]()
+	var calls int64
+
+	o.On("foo", &sleepEvent{10 * time.Millisecond, &calls}, &sleepEvent{20 * time.Millisecond, &calls})
+	o.On("bar", &sleepEvent{10 * time.Millisecond, &calls})
+
+	dones := []<-chan struct{}{
+		o.TriggerAsync("foo", "1"),
+		o.TriggerAsync("bar", "2"),
+		o.TriggerAsync("baz", "3"),
+	}
+	for _, done := range dones {
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			t.Fatal("The async trigger is not done")
+		}
+	}
+
+	if c := atomic.LoadInt64(&calls); c != 3 {
+		t.Errorf("The counter is %d instead of being %d", c, 3)
+	}
+
+	// the channel without any handler is closed too
+	select {
+	case _, ok := <-dones[2]:
+		if ok {
+			t.Error("The channel should be closed")
+		}
+	default:
+		t.Error("The channel should be closed")
+	}
+}
+
+func TestFlush(t *testing.T) {
+	o := New[string]().Debounce("bar", time.Hour)
+	var calls int64
+
+	// nothing to flush
+	o.Flush()
+
+	o.On("foo", &sleepEvent{10 * time.Millisecond, &calls}).On("bar", &sleepEvent{0, &calls})
+	for i := 0; i < 10; i++ {
+		o.TriggerAsync("foo", "1")
+	}
+	o.Trigger("bar", "1").Trigger("bar", "2")
+
+	o.Flush()
+	if c := atomic.LoadInt64(&calls); c != 11 {
+		t.Errorf("The counter is %d instead of being %d", c, 11)
+	}
+
+	// the bus accepts the new messages after Flush
+	o.TriggerAsync("foo", "2")
+	o.Flush()
+	if c := atomic.LoadInt64(&calls); c != 12 {
+		t.Errorf("The counter is %d instead of being %d", c, 12)
+	}
+}
+
+func TestShutdown(t *testing.T) {
+	o := New[string]().Debounce("bar", time.Hour)
+	var calls int64
+
+	stopped := &ttlEvent{stopped: make(chan string, 1)}
+	o.On("foo", &sleepEvent{10 * time.Millisecond, &calls}).On("bar", &sleepEvent{0, &calls}).On("baz", stopped)
+	o.TriggerAsync("foo")
+	o.Trigger("bar")
+	if err := o.Shutdown(context.Background()); err != nil {
+		t.Errorf("The error is %v instead of being nil", err)
+	}
+	if c := atomic.LoadInt64(&calls); c != 2 {
+		t.Errorf("The counter is %d instead of being %d", c, 2)
+	}
+	select {
+	case <-stopped.stopped:
+	default:
+		t.Error("The event is not stopped")
+	}
+
+	// the bus accepts nothing after the shutdown
+	if err := o.TryOn("foo", stopped); err != ErrClosed {
+		t.Errorf("The error is %v instead of being %v", err, ErrClosed)
+	}
+	o.OnTTL("foo", time.Hour, stopped)
+	<-o.TriggerAsync("baz")
+	if c := o.TopicCount(); c != 0 || stopped.n != 0 {
+		t.Errorf("The topic count is %d instead of being %d", c, 0)
+	}
+
+	// the done context returns promptly
+	o = New[string]()
+	o.On("foo", &sleepEvent{time.Second, &calls})
+	o.TriggerAsync("foo")
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	start := time.Now()
+	if err := o.Shutdown(ctx); err != context.Canceled {
+		t.Errorf("The error is %v instead of being %v", err, context.Canceled)
+	}
+	if d := time.Since(start); d > 500*time.Millisecond {
+		t.Errorf("The shutdown takes %s", d)
+	}
+}
+
+type concurrentEvent struct {
+	running *int64
+	max     *int64
+	calls   *int64
+}
+
+func (e *concurrentEvent) Dispatch(topic string, data ...string) {
+	n := atomic.AddInt64(e.running, 1)
+	for {
+		max := atomic.LoadInt64(e.max)
+		if n <= max || atomic.CompareAndSwapInt64(e.max, max, n) {
+			break
+		}
+	}
+	time.Sleep(time.Millisecond)
+	atomic.AddInt64(e.running, -1)
+	atomic.AddInt64(e.calls, 1)
+}
+
+func TestBroadcastConcurrency(t *testing.T) {
+	o := New[string]().WithBroadcastConcurrency(4)
+	var running, max, calls int64
+
+	for i := 0; i < 100; i++ {
+		o.On(fmt.Sprintf("topic-%d", i), &concurrentEvent{&running, &max, &calls})
+	}
+	o.Broadcast("1")
+	if c := atomic.LoadInt64(&calls); c != 100 {
+		t.Errorf("The counter is %d instead of being %d", c, 100)
+	}
+	if m := atomic.LoadInt64(&max); m > 4 || m < 2 {
+		t.Errorf("The max concurrency is %d instead of being at most %d", m, 4)
+	}
+
+	// the topics are dispatched one by one by default
+	max = 0
+	o.WithBroadcastConcurrency(0).Broadcast("2")
+	if m := atomic.LoadInt64(&max); m != 1 {
+		t.Errorf("The max concurrency is %d instead of being %d", m, 1)
+	}
+}
+
+func TestBroadcastParallel(t *testing.T) {
+	o := New[string]()
+	var running, max, calls int64
+
+	for i := 0; i < 100; i++ {
+		o.On(fmt.Sprintf("topic-%d", i), &concurrentEvent{&running, &max, &calls})
+	}
+
+	// the topics are modified while they are dispatched
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 100; i < 200; i++ {
+			e := &concurrentEvent{new(int64), new(int64), new(int64)}
+			o.On(fmt.Sprintf("topic-%d", i), e).Off(fmt.Sprintf("topic-%d", i), e)
+		}
+	}()
+	o.BroadcastParallel(8, "1")
+	<-done
+
+	if c := atomic.LoadInt64(&calls); c != 100 {
+		t.Errorf("The counter is %d instead of being %d", c, 100)
+	}
+	if m := atomic.LoadInt64(&max); m > 8 || m < 2 {
+		t.Errorf("The max concurrency is %d instead of being at most %d", m, 8)
+	}
+}
+
+// 基准测试：顺序广播到慢处理函数
+func BenchmarkBroadcast(b *testing.B) {
+	bus := New[string]()
+	var calls int64
+	for i := 0; i < 100; i++ {
+		bus.On(fmt.Sprintf("topic-%d", i), &sleepEvent{100 * time.Microsecond, &calls})
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		bus.Broadcast("test message")
+	}
+}
+
+// 基准测试：并发广播到慢处理函数
+func BenchmarkBroadcastParallel(b *testing.B) {
+	bus := New[string]()
+	var calls int64
+	for i := 0; i < 100; i++ {
+		bus.On(fmt.Sprintf("topic-%d", i), &sleepEvent{100 * time.Microsecond, &calls})
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		bus.BroadcastParallel(16, "test message")
+	}
+}
+
+func TestParallelTopic(t *testing.T) {
+	o := New[string]().ParallelTopic("foo")
+	var calls int64
+
+	for i := 0; i < 5; i++ {
+		o.On("foo", &sleepEvent{50 * time.Millisecond, &calls})
+	}
+	for i := 0; i < 5; i++ {
+		o.Once("foo", &sleepEvent{50 * time.Millisecond, &calls})
+	}
+
+	start := time.Now()
+	o.Trigger("foo", "1")
+	if d := time.Since(start); d > 400*time.Millisecond {
+		t.Errorf("The parallel dispatch takes %s", d)
+	}
+	if c := atomic.LoadInt64(&calls); c != 10 {
+		t.Errorf("The counter is %d instead of being %d", c, 10)
+	}
+	if c := o.EventCount("foo"); c != 5 {
+		t.Errorf("The event count is %d instead of being %d", c, 5)
+	}
+
+	o.Trigger("foo", "2")
+	if c := atomic.LoadInt64(&calls); c != 15 {
+		t.Errorf("The counter is %d instead of being %d", c, 15)
+	}
+}
@@ -0,0 +1,68 @@
+package eventbus
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// inflight struct, it counts the running dispatches by epoch, so that a barrier can wait for the dispatches
+// started before it without blocking the new ones
+type inflight struct {
+	mu      sync.Mutex
+	epoch   atomic.Uint64
+	counts  [2]atomic.Int64
+	drained atomic.Pointer[drain]
+}
+
+// drain struct, the barrier waiting for the dispatches of an epoch, done is closed by the last of them
+type drain struct {
+	index uint64
+	done  chan struct{}
+}
+
+// enter registers a running dispatch and returns its epoch, exit must be called with it after the dispatch returns
+func (f *inflight) enter() uint64 {
+	for {
+		epoch := f.epoch.Load()
+		f.counts[epoch&1].Add(1)
+		// the epoch is checked again, since the barrier may have moved on before the dispatch is counted
+		if f.epoch.Load() == epoch {
+			return epoch
+		}
+		f.exit(epoch)
+	}
+}
+
+// exit unregisters a dispatch entered in the epoch, the last dispatch of the epoch wakes the barrier waiting for it
+func (f *inflight) exit(epoch uint64) {
+	if f.counts[epoch&1].Add(-1) != 0 {
+		return
+	}
+	if d := f.drained.Load(); d != nil && d.index == epoch&1 && f.drained.CompareAndSwap(d, nil) {
+		close(d.done)
+	}
+}
+
+// wait starts a new epoch and blocks until the dispatches of the previous epoch return
+func (f *inflight) wait() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	d := &drain{index: f.epoch.Add(1)&1 ^ 1, done: make(chan struct{})}
+	f.drained.Store(d)
+	// the dispatches may have returned before the barrier is stored
+	if f.counts[d.index].Load() == 0 && f.drained.CompareAndSwap(d, nil) {
+		return
+	}
+	<-d.done
+}
+
+// CleanBarrier - clear all events like CleanSync and wait for the running dispatches, so that no removed event
+// is called after it returns. Unlike Clean, it blocks until the slowest handler returns, and it must not be called
+// by a handler since it would wait for itself. The dispatches never wait for it, they are only counted by atomic
+// operations. The handlers left running by WithHandlerTimeout are not waited for
+func (b *Bus[T]) CleanBarrier() *Bus[T] {
+	es := b.clean()
+	b.inflight.wait()
+	b.onStop(es)
+	return b
+}
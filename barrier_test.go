@@ -0,0 +1,63 @@
+package eventbus
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type cleanedEvent struct {
+	cleaned *atomic.Bool
+	late    *atomic.Int64
+}
+
+func (e *cleanedEvent) Dispatch(topic string, data ...string) {
+	time.Sleep(time.Microsecond)
+	if e.cleaned.Load() {
+		e.late.Add(1)
+	}
+}
+
+func TestCleanBarrier(t *testing.T) {
+	o := New[string]()
+	var (
+		cleaned atomic.Bool
+		late    atomic.Int64
+		stop    atomic.Bool
+		wg      sync.WaitGroup
+		started sync.WaitGroup
+	)
+
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		started.Add(1)
+		go func() {
+			defer wg.Done()
+			started.Done()
+			for !stop.Load() {
+				o.Trigger("foo", "test")
+				o.Broadcast("test")
+			}
+		}()
+	}
+
+	started.Wait()
+	// the handlers of the dispatches running during the clean must return before CleanBarrier
+	for i := 0; i < 20; i++ {
+		cleaned.Store(false)
+		o.On("foo", &cleanedEvent{&cleaned, &late}).On(ALL, &cleanedEvent{&cleaned, &late})
+		time.Sleep(time.Millisecond)
+		o.CleanBarrier()
+		cleaned.Store(true)
+		if c := o.EventCount("foo"); c != 0 {
+			t.Errorf("The event count is %d instead of being %d", c, 0)
+		}
+	}
+	stop.Store(true)
+	wg.Wait()
+
+	if n := late.Load(); n != 0 {
+		t.Errorf("The events are called %d times after CleanBarrier returns", n)
+	}
+}
@@ -0,0 +1,47 @@
+package eventbus
+
+import (
+	"slices"
+)
+
+// bridge struct, it forwards the messages of the source bus to the destination bus
+type bridge[T any] struct {
+	src *Bus[T]
+	dst *Bus[T]
+}
+
+func (f *bridge[T]) Dispatch(topic string, data ...T) {
+	f.forward(newMessage(topic, data))
+}
+
+// clone returns the bridge forwarding the messages of b
+func (f *bridge[T]) clone(b *Bus[T]) Event[T] {
+	return &bridge[T]{b, f.dst}
+}
+
+// forward triggers m on the destination bus, the messages which have passed
+// through the destination bus are dropped to avoid infinite loops
+func (f *bridge[T]) forward(m *message[T]) {
+	if f.dst == f.src || slices.Contains(m.route, f.dst) {
+		return
+	}
+	route := append(m.route[:len(m.route):len(m.route)], f.src)
+	f.dst.dispatch(f.dst.Get(m.topic), &message[T]{topic: m.topic, data: m.data, route: route, meta: m.meta})
+}
+
+// Bridge - forward the messages of the topics to dst, all the topics are forwarded if no topic is given,
+// which relies on the ALL topic and asterisk. It returns the func which removes the bridge
+func (b *Bus[T]) Bridge(dst *Bus[T], topics ...string) func() {
+	if len(topics) == 0 {
+		topics = []string{ALL}
+	}
+	f := &bridge[T]{b, dst}
+	for _, topic := range topics {
+		b.On(topic, f)
+	}
+	return func() {
+		for _, topic := range topics {
+			b.Off(topic, f)
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package eventbus
+
+import (
+	"testing"
+)
+
+func TestBridge(t *testing.T) {
+	a := New[string]()
+	b := New[string]()
+
+	onA := &topicEvent{}
+	onB := &topicEvent{}
+	a.On("foo", onA)
+	b.On("foo", onB).On("bar", onB)
+
+	off := a.Bridge(b, "foo")
+	a.Trigger("foo", "1").Trigger("bar", "2")
+	if onA.n != 1 {
+		t.Errorf("The counter of a is %d instead of being %d", onA.n, 1)
+	}
+	if onB.n != 1 || onB.topic != "foo" {
+		t.Errorf("The counter of b is %d instead of being %d", onB.n, 1)
+	}
+
+	off()
+	a.Trigger("foo", "3")
+	if onB.n != 1 {
+		t.Errorf("The counter of b is %d instead of being %d", onB.n, 1)
+	}
+	if c := a.EventCount("foo"); c != 1 {
+		t.Errorf("The event count is %d instead of being %d", c, 1)
+	}
+}
+
+func TestBridgeLoop(t *testing.T) {
+	a := New[string]()
+	b := New[string]()
+	c := New[string]()
+
+	onA := &topicEvent{}
+	onB := &topicEvent{}
+	onC := &topicEvent{}
+	a.On("foo", onA)
+	b.On("foo", onB)
+	c.On("foo", onC)
+
+	// a <-> b, and a -> c -> b
+	a.Bridge(b)
+	b.Bridge(a)
+	a.Bridge(c)
+	c.Bridge(b)
+
+	a.Trigger("foo", "1")
+	if onA.n != 1 {
+		t.Errorf("The counter of a is %d instead of being %d", onA.n, 1)
+	}
+	if onB.n != 2 {
+		t.Errorf("The counter of b is %d instead of being %d", onB.n, 2)
+	}
+	if onC.n != 1 {
+		t.Errorf("The counter of c is %d instead of being %d", onC.n, 1)
+	}
+
+	b.Trigger("foo", "2")
+	if onA.n != 2 {
+		t.Errorf("The counter of a is %d instead of being %d", onA.n, 2)
+	}
+	if onB.n != 3 {
+		t.Errorf("The counter of b is %d instead of being %d", onB.n, 3)
+	}
+	if onC.n != 2 {
+		t.Errorf("The counter of c is %d instead of being %d", onC.n, 2)
+	}
+}
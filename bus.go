@@ -1,131 +1,1428 @@
 package eventbus
 
 import (
+	"context"
+	"errors"
+	"log/slog"
 	"reflect"
+	"slices"
+	"strings"
+	"sync"
 	"sync/atomic"
-
-	"github.com/lockp111/go-cmap"
+	"time"
 )
 
+// ErrMaxTopics - the error returned when a new topic is subscribed while the number of the topics reaches the limit
+var ErrMaxTopics = errors.New("eventbus: the number of the topics reaches the limit")
+
+// ErrEmptyTopic - the error returned by TryOn when the topic is empty
+var ErrEmptyTopic = errors.New("eventbus: the topic is empty")
+
+// ErrClosed - the error returned when an event is subscribed after Shutdown
+var ErrClosed = errors.New("eventbus: the bus is shut down")
+
 // Bus struct
 type Bus[T any] struct {
-	events cmap.ConcurrentMap[string, []*event[T]]
+	topics        Store[T]
+	allowAsterisk bool
+	onceMu        sync.Mutex
+	onces         map[reflect.Value]*onceGuard[T]
+	noListener    func(topic string, data []T)
+	dropHandler   func(topic string, reason DropReason, data []T)
+	dropped       atomic.Uint64
+	timeout       time.Duration
+	slowHandler   func(topic string, e Event[T], d time.Duration)
+	panicHandler  func(topic string, e Event[T], v any)
+	lifoStop      bool
+	histories     lazyMap[*history[T]]
+	debouncers    lazyMap[*debouncer[T]]
+	errHandler    func(topic string, e ErrEvent[T], err error)
+	stopTimeout   time.Duration
+	asteriskFirst bool
+	bubbleSep     string
+	dedup         atomic.Pointer[dedupCache]
+	dispatches    atomic.Uint64
+	copyPayload   bool
+	parallels     lazyMap[bool]
+	renameMu      sync.Mutex
+	meta          atomic.Pointer[Bus[MetaEvent]]
+	broadcastAll  bool
+	seq           atomic.Uint64
+	pending       pending
+	pause         pause
+	inflight      inflight
+	stopper       stopper
+	timing        bool
+	latencies     lazyMap[*latency]
+	batchStop     func(topic string, handlers []Event[T])
+	dedupDispatch bool
+	maxTopics     int
+	limitMu       sync.Mutex
+	closed        atomic.Bool
+	broadcastConc int
+	logger        *slog.Logger
+	leakThreshold int
+	leakHandler   func(topic string, count int)
+	keyWorkers    int
+	keyOnce       sync.Once
+	keyMu         sync.RWMutex
+	keyQueues     []chan keyedMessage[T]
+	capacities    lazyMap[int]
 }
 
 // New - return a new Bus object
 func New[T any]() *Bus[T] {
 	return &Bus[T]{
-		events: cmap.New[[]*event[T]](),
+		topics:        NewCMapStore[T](),
+		allowAsterisk: true,
+		broadcastAll:  true,
+		onces:         make(map[reflect.Value]*onceGuard[T]),
 	}
 }
 
+// NewWithAsterisk - return a new Bus object whose ALL events receive the messages of every topic,
+// it is the same as New since asterisk is allowed by default
+func NewWithAsterisk[T any]() *Bus[T] {
+	return New[T]().AllowAsterisk(true)
+}
+
+// WithStore - set the map of the topics, default is NewCMapStore. It must be called before subscribing
+// since the topics of the previous store are dropped
+func (b *Bus[T]) WithStore(store Store[T]) *Bus[T] {
+	b.topics = store
+	return b
+}
+
+// WithTopicResolver - rewrite the topics before they are stored or looked up, so that the subscriptions and the
+// triggers of the same topic land on the same resolved key. The events still receive the topics which are not
+// rewritten. It must be called before subscribing and after WithStore
+func (b *Bus[T]) WithTopicResolver(resolve func(topic string) string) *Bus[T] {
+	b.topics = &resolvedStore[T]{Store: b.topics, resolve: resolve}
+	return b
+}
+
+// WithMaxTopics - limit the number of the topics, the events subscribed to a new topic by any method are rejected
+// when the limit is reached while the existing topics keep working. Use TryOn to get the error, n <= 0 removes the limit
+func (b *Bus[T]) WithMaxTopics(n int) *Bus[T] {
+	b.maxTopics = n
+	return b
+}
+
+// AllowAsterisk - set whether the ALL events receive the messages of every topic, default is true
+func (b *Bus[T]) AllowAsterisk(allow bool) *Bus[T] {
+	b.allowAsterisk = allow
+	return b
+}
+
+// BroadcastSkipAll - skip the ALL topic on Broadcast, so that the ALL events are not called by Broadcast
+func (b *Bus[T]) BroadcastSkipAll() *Bus[T] {
+	b.broadcastAll = false
+	return b
+}
+
+// DedupPerDispatch - call each handler at most once per dispatch, even if it is registered several times
+// to the topic or to both the topic and ALL
+func (b *Bus[T]) DedupPerDispatch() *Bus[T] {
+	b.dedupDispatch = true
+	return b
+}
+
+// WithBroadcastConcurrency - dispatch the topics of Broadcast on at most n goroutines, Broadcast waits for a free
+// goroutine before dispatching the next topic and returns after all the topics are dispatched. n <= 1 dispatches them one by one
+func (b *Bus[T]) WithBroadcastConcurrency(n int) *Bus[T] {
+	b.broadcastConc = n
+	return b
+}
+
+// WithTopicCapacity - preallocate the events of the topic for capacity events, so that subscribing up to
+// capacity events doesn't reallocate them. capacity <= 0 removes the hint
+func (b *Bus[T]) WithTopicCapacity(topic string, capacity int) *Bus[T] {
+	if capacity <= 0 {
+		b.capacities.remove(topic)
+	} else {
+		b.capacities.store().Set(topic, capacity)
+	}
+	if t, ok := b.topics.Get(topic); ok {
+		t.setCapacity(capacity)
+	}
+	return b
+}
+
+// ParallelTopic - call the topic events in parallel, the dispatch returns after all of them return,
+// so the events of the topic must be safe to be called concurrently with each other
+func (b *Bus[T]) ParallelTopic(topic string) *Bus[T] {
+	b.parallels.store().Set(topic, true)
+	return b
+}
+
+// CopyPayload - pass each handler a copy of the data, so that a handler modifying the data never affects the others
+func (b *Bus[T]) CopyPayload() *Bus[T] {
+	b.copyPayload = true
+	return b
+}
+
+// AsteriskFirst - dispatch to the ALL events before the topic events, default is after
+func (b *Bus[T]) AsteriskFirst() *Bus[T] {
+	b.asteriskFirst = true
+	return b
+}
+
+// EnableBubbling - dispatch the messages of a topic also to its registered ancestor topics split by sep,
+// from the child to the parent, e.g. a.b.c bubbles up to a.b and a. The events of the ancestors receive
+// the original topic, the ALL events are still called once per message
+func (b *Bus[T]) EnableBubbling(sep string) *Bus[T] {
+	b.bubbleSep = sep
+	return b
+}
+
+// OnNoListener - set the callback which is called when a message is emitted to a topic without any listener,
+// the ALL events are counted as listeners when asterisk is allowed
+func (b *Bus[T]) OnNoListener(fn func(topic string, data []T)) *Bus[T] {
+	b.noListener = fn
+	return b
+}
+
+// WithHandlerTimeout - set the timeout of each handler, the handler is called on a child goroutine
+// and the dispatch moves on when the timeout expires, the handler keeps running in the background
+func (b *Bus[T]) WithHandlerTimeout(d time.Duration) *Bus[T] {
+	b.timeout = d
+	return b
+}
+
+// OnSlowHandler - set the callback which is called when a handler exceeds the handler timeout
+func (b *Bus[T]) OnSlowHandler(fn func(topic string, e Event[T], d time.Duration)) *Bus[T] {
+	b.slowHandler = fn
+	return b
+}
+
+// OnBatchStop - set the callback which receives the removed events of each topic at once,
+// it is called after OnStop of the events for every removal
+func (b *Bus[T]) OnBatchStop(fn func(topic string, handlers []Event[T])) *Bus[T] {
+	b.batchStop = fn
+	return b
+}
+
+// OnHandlerLeak - set the callback which is called when the number of the topic events exceeds the threshold,
+// it is called once each time the count crosses the threshold
+func (b *Bus[T]) OnHandlerLeak(threshold int, fn func(topic string, count int)) *Bus[T] {
+	b.leakThreshold = threshold
+	b.leakHandler = fn
+	return b
+}
+
+// OnPanic - set the callback which receives the panics of Dispatch and OnStop instead of crashing
+func (b *Bus[T]) OnPanic(fn func(topic string, e Event[T], v any)) *Bus[T] {
+	b.panicHandler = fn
+	return b
+}
+
+// WithStopTimeout - set the deadline of the context passed to OnStopCtx
+func (b *Bus[T]) WithStopTimeout(d time.Duration) *Bus[T] {
+	b.stopTimeout = d
+	return b
+}
+
+// LIFOStop - call OnStop of the removed events in reverse registration order, like defer
+func (b *Bus[T]) LIFOStop() *Bus[T] {
+	b.lifoStop = true
+	return b
+}
+
 // On - register topic event and return error
 func (b *Bus[T]) On(topic string, e ...Event[T]) *Bus[T] {
 	b.addEvents(topic, false, e)
 	return b
 }
 
+// OnMany - register event to each of the topics
+func (b *Bus[T]) OnMany(topics []string, e Event[T]) *Bus[T] {
+	for _, topic := range topics {
+		b.On(topic, e)
+	}
+	return b
+}
+
+// TryOn - register topic event, ErrMaxTopics is returned if the topic is new and the number of the topics reaches the limit,
+// ErrEmptyTopic is returned if the topic is empty while On accepts it
+func (b *Bus[T]) TryOn(topic string, e ...Event[T]) error {
+	if topic == "" {
+		return ErrEmptyTopic
+	}
+	_, err := b.addEvents(topic, false, e)
+	return err
+}
+
+// OnExcept - register ALL event which skips the messages of the topics matching any of the excluded prefixes
+func (b *Bus[T]) OnExcept(excludePrefixes []string, e Event[T]) *Bus[T] {
+	ev := newEvent(e, ALL, false)
+	ev.excludes = slices.Clone(excludePrefixes)
+	b.insertEvents(ALL, []*event[T]{ev})
+	return b
+}
+
+// OnCount - register topic event and return the number of the topic events after registration
+func (b *Bus[T]) OnCount(topic string, e Event[T]) int {
+	n, _ := b.addEvents(topic, false, []Event[T]{e})
+	return n
+}
+
 // Once - register once event and return error
 func (b *Bus[T]) Once(topic string, e ...Event[T]) *Bus[T] {
 	b.addEvents(topic, true, e)
 	return b
 }
 
-// Off - remove topic event
+// Off - remove topic event, all the topic events are removed like OffAll if no event is given.
+// It is safe to be called inside Dispatch, the removal takes effect after the current dispatch loop completes
 func (b *Bus[T]) Off(topic string, e ...Event[T]) *Bus[T] {
+	if len(e) == 0 {
+		b.OffAll(topic)
+		return b
+	}
 	b.removeEvents(topic, e)
 	return b
 }
 
-// Clean - clear all events
+// OffMany - remove event from each of the topics, OnStop is called for every topic
+func (b *Bus[T]) OffMany(topics []string, e Event[T]) *Bus[T] {
+	for _, topic := range topics {
+		b.removeEvents(topic, []Event[T]{e})
+	}
+	return b
+}
+
+// SetOnce - turn e of the topic into a once event or into a persistent event in place, it keeps its order.
+// It returns whether e is registered to the topic and not consumed as a once event. The events registered
+// with a Subscription are not changed
+func (b *Bus[T]) SetOnce(topic string, e Event[T], once bool) bool {
+	t, ok := b.topics.Get(topic)
+	if !ok {
+		return false
+	}
+	var (
+		tag      = tagOf(e)
+		found    bool
+		released []*event[T]
+	)
+	t.replaceEvents(func(v *event[T]) *event[T] {
+		if v.tag != tag || v.owned || (v.isUnique && atomic.LoadUint32(&v.guard.hasCalled) == 1) {
+			return nil
+		}
+		found = true
+		if v.isUnique == once {
+			return nil
+		}
+		ev := v.withOnce(once)
+		if once {
+			b.guardOnce(ev)
+		} else {
+			released = append(released, v)
+		}
+		return ev
+	})
+	b.releaseOnce(released)
+	return found
+}
+
+// Suspend - skip e in the dispatches of the topic until Unsuspend, e keeps its registration and its order.
+// A suspended once event is not consumed
+func (b *Bus[T]) Suspend(topic string, e Event[T]) *Bus[T] {
+	b.setSuspended(topic, e, true)
+	return b
+}
+
+// Unsuspend - dispatch to e again after Suspend
+func (b *Bus[T]) Unsuspend(topic string, e Event[T]) *Bus[T] {
+	b.setSuspended(topic, e, false)
+	return b
+}
+
+func (b *Bus[T]) setSuspended(topic string, e Event[T], suspended bool) {
+	t, ok := b.topics.Get(topic)
+	if !ok {
+		return
+	}
+	tag := tagOf(e)
+	for _, v := range t.snapshot() {
+		if v.tag == tag {
+			v.suspended.Store(suspended)
+		}
+	}
+}
+
+// OffAll - remove all the topic events and return the number of the removed events, OnStop is called
+func (b *Bus[T]) OffAll(topic string) int {
+	return len(b.removeFunc(topic, func(e *event[T]) bool {
+		return true
+	}))
+}
+
+// OffFunc - remove the topic events for which match returns true and return the number of the removed events,
+// OnStop is called. match is called with the topic locked, so it must not call the bus
+func (b *Bus[T]) OffFunc(topic string, match func(e Event[T]) bool) int {
+	return len(b.removeFunc(topic, func(e *event[T]) bool {
+		return match(e.handler())
+	}))
+}
+
+// OffIndex - remove the topic event at index in the registration order and return it, OnStop is called.
+// It returns false if index is out of range
+func (b *Bus[T]) OffIndex(topic string, index int) (Event[T], bool) {
+	if index < 0 {
+		return nil, false
+	}
+	i := 0
+	removed := b.removeFunc(topic, func(e *event[T]) bool {
+		i++
+		return i-1 == index
+	})
+	if len(removed) == 0 {
+		return nil, false
+	}
+	return removed[0].handler(), true
+}
+
+// Clean - clear all events, OnStop of the removed events is called later on the stop goroutine of the bus
 func (b *Bus[T]) Clean() *Bus[T] {
-	b.events = cmap.New[[]*event[T]]()
+	b.stopLater(b.clean())
 	return b
 }
 
-// Trigger - dispatch event
+// CleanSync - clear all events, it returns after OnStop of every removed event is called
+func (b *Bus[T]) CleanSync() *Bus[T] {
+	b.onStop(b.clean())
+	return b
+}
+
+// Close - clear all events, it returns after OnStop of every removed event is called,
+// OnStopCtx receives ctx limited by the stop timeout. It returns the error of ctx if ctx is done
+func (b *Bus[T]) Close(ctx context.Context) error {
+	stopCtx, cancel := b.stopContext(ctx)
+	defer cancel()
+	b.onStopCtx(stopCtx, b.clean())
+	return ctx.Err()
+}
+
+// Shutdown - stop accepting new events and messages, give up the pending retries of OnRetry, wait for the async
+// and the debounced messages, then clear all events like Close. It returns the error of ctx if ctx is done before the messages are delivered
+func (b *Bus[T]) Shutdown(ctx context.Context) error {
+	b.closed.Store(true)
+	b.haltRetries()
+	// no message is queued after the workers are stopped, the queued ones are still dispatched
+	b.stopKeyWorkers()
+	flushed := make(chan struct{})
+	go func() {
+		defer close(flushed)
+		b.Flush()
+	}()
+	select {
+	case <-flushed:
+	case <-ctx.Done():
+	}
+	return b.Close(ctx)
+}
+
+// Emit - dispatch msg to the topic events, and to the ALL events when asterisk is allowed
+func (b *Bus[T]) Emit(topic string, msg ...T) *Bus[T] {
+	b.dispatch(b.Get(topic), newMessage(topic, msg))
+	return b
+}
+
+// Trigger - dispatch event, same as Emit
 func (b *Bus[T]) Trigger(topic string, msg ...T) *Bus[T] {
-	b.dispatch(topic, msg)
+	return b.Emit(topic, msg...)
+}
+
+// TriggerValue - dispatch a single msg like Trigger, the data is stored in the message so that
+// no slice is allocated for it
+func (b *Bus[T]) TriggerValue(topic string, msg T) *Bus[T] {
+	b.dispatch(b.Get(topic), newValueMessage(topic, msg))
 	return b
 }
 
-func (b *Bus[T]) addEvents(topic string, isUnique bool, es []Event[T]) {
-	if len(es) == 0 {
+// TriggerDirect - dispatch msg to the topic events only, the ALL events are skipped even if asterisk is allowed
+func (b *Bus[T]) TriggerDirect(topic string, msg ...T) *Bus[T] {
+	m := newMessage(topic, msg)
+	m.direct = true
+	b.dispatch(b.Get(topic), m)
+	return b
+}
+
+// TriggerMany - dispatch msg to each of the topics like Trigger, the ALL events receive it once per topic.
+// The data is shared by the messages of all the topics
+func (b *Bus[T]) TriggerMany(topics []string, msg ...T) *Bus[T] {
+	for _, topic := range topics {
+		b.dispatch(b.Get(topic), newMessage(topic, msg))
+	}
+	return b
+}
+
+// TriggerThen - dispatch event and call done with the number of the called events after the dispatch completes.
+// done is called later if the message is held by PauseAll or Debounce, and with 0 if the message is dropped
+func (b *Bus[T]) TriggerThen(topic string, done func(handlers int), msg ...T) *Bus[T] {
+	m := newMessage(topic, msg)
+	m.then = done
+	b.dispatch(b.Get(topic), m)
+	return b
+}
+
+// TriggerIf - dispatch event only if cond returns true for the number of the topic events, the ALL events and the
+// fallback events are not counted. The message is dispatched to the events counted which are still registered
+// when it is dispatched, so the events subscribed meanwhile are not called. It returns whether the message is dispatched
+func (b *Bus[T]) TriggerIf(topic string, cond func(count int) bool, msg ...T) bool {
+	t := b.Get(topic)
+	events := t.snapshot()
+	count := 0
+	for _, e := range events {
+		if !e.fallback {
+			count++
+		}
+	}
+	if !cond(count) {
+		return false
+	}
+	m := newMessage(topic, msg)
+	m.pinned, m.pin = events, true
+	b.dispatch(t, m)
+	return true
+}
+
+// TriggerTo - dispatch msg to the target event of the topic only, it returns whether the target is registered
+// to the topic and called. The target registered by Once is removed after it is called. msg is dropped
+// after Shutdown or while the bus is paused, since it is never buffered
+func (b *Bus[T]) TriggerTo(topic string, target Event[T], msg ...T) bool {
+	if !b.admit(topic, msg) {
+		return false
+	}
+	t, ok := b.topics.Get(topic)
+	if !ok {
+		return false
+	}
+	epoch := b.inflight.enter()
+	defer b.inflight.exit(epoch)
+	m := newMessage(topic, msg)
+	m.seq = b.seq.Add(1)
+	tag := tagOf(target)
+	for _, e := range t.snapshot() {
+		// the once event which has been called is skipped
+		if e.tag == tag && b.callEvents(topic, []*event[T]{e}, m) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// TriggerCollect - dispatch event and return the once events called and removed by the dispatch
+func (b *Bus[T]) TriggerCollect(topic string, msg ...T) []Event[T] {
+	m := newMessage(topic, msg)
+	m.collect = true
+	b.dispatch(b.Get(topic), m)
+	return m.consumed
+}
+
+// TriggerReport - dispatch event and return the report of the dispatch
+func (b *Bus[T]) TriggerReport(topic string, msg ...T) DispatchReport {
+	m := newMessage(topic, msg)
+	m.report = &DispatchReport{}
+	b.dispatch(b.Get(topic), m)
+	return *m.report
+}
+
+// TriggerSlice - dispatch data to the topic events like Emit, the slice is shared with the handlers unless CopyPayload is set
+func (b *Bus[T]) TriggerSlice(topic string, data []T) *Bus[T] {
+	b.dispatch(b.Get(topic), newMessage(topic, data))
+	return b
+}
+
+// TriggerMeta - dispatch event with the metadata like a trace id, the events which implement EventMeta receive it
+func (b *Bus[T]) TriggerMeta(meta map[string]string, topic string, msg ...T) *Bus[T] {
+	m := newMessage(topic, msg)
+	m.meta = meta
+	b.dispatch(b.Get(topic), m)
+	return b
+}
+
+// Broadcast - dispatch msg to every topic once, the ALL topic is treated as an ordinary topic
+func (b *Bus[T]) Broadcast(msg ...T) *Bus[T] {
+	if b.broadcastAll {
+		b.broadcast(msg, nil, b.broadcastConc, nil)
+	} else {
+		b.broadcast(msg, []string{ALL}, b.broadcastConc, nil)
+	}
+	return b
+}
+
+// BroadcastParallel - dispatch msg to every topic once like Broadcast, the topics are dispatched on at most
+// concurrency goroutines. It returns after all the topics are dispatched
+func (b *Bus[T]) BroadcastParallel(concurrency int, msg ...T) *Bus[T] {
+	if b.broadcastAll {
+		b.broadcast(msg, nil, concurrency, nil)
+	} else {
+		b.broadcast(msg, []string{ALL}, concurrency, nil)
+	}
+	return b
+}
+
+// BroadcastCollect - dispatch msg to every topic once like Broadcast and return the once events called
+// and removed by it per topic
+func (b *Bus[T]) BroadcastCollect(msg ...T) map[string][]Event[T] {
+	var (
+		mu       sync.Mutex
+		consumed = make(map[string][]Event[T])
+	)
+	except := []string(nil)
+	if !b.broadcastAll {
+		except = []string{ALL}
+	}
+	b.broadcast(msg, except, b.broadcastConc, func(topic string, es []Event[T]) {
+		mu.Lock()
+		defer mu.Unlock()
+		consumed[topic] = es
+	})
+	return consumed
+}
+
+// BroadcastExcept - dispatch msg to every topic once except the given topics, pass ALL to skip the ALL events
+func (b *Bus[T]) BroadcastExcept(except []string, msg ...T) *Bus[T] {
+	b.broadcast(msg, except, b.broadcastConc, nil)
+	return b
+}
+
+// TopicCount - return the number of the topics
+func (b *Bus[T]) TopicCount() int {
+	return b.topics.Count()
+}
+
+// Exists - return whether the topic is stored, the topics are removed once they are empty,
+// so an existing topic without any event is seen only transiently or with a custom store
+func (b *Bus[T]) Exists(topic string) bool {
+	_, ok := b.topics.Get(topic)
+	return ok
+}
+
+// EventCount - return the number of the topic events
+func (b *Bus[T]) EventCount(topic string) int {
+	if t, ok := b.topics.Get(topic); ok {
+		return t.count()
+	}
+	return 0
+}
+
+// CountSnapshot - return the number of the events of every topic in a single pass over the topics,
+// the count of each topic is consistent while the topics are not changed at the same time
+func (b *Bus[T]) CountSnapshot() map[string]int {
+	counts := make(map[string]int, b.topics.Count())
+	b.topics.IterCb(func(topic string, t *Topic[T]) {
+		counts[topic] = t.count()
+	})
+	return counts
+}
+
+// Has - return whether e is registered to the topic
+func (b *Bus[T]) Has(topic string, e Event[T]) bool {
+	t, ok := b.topics.Get(topic)
+	if !ok {
+		return false
+	}
+	tag := tagOf(e)
+	for _, v := range t.snapshot() {
+		if v.tag == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// EachEvent - call fn for every event of the topic in registration order without removing them,
+// fn iterates a snapshot of the events, so it can subscribe or unsubscribe the events safely
+func (b *Bus[T]) EachEvent(topic string, fn func(e Event[T], isOnce bool)) {
+	t, ok := b.topics.Get(topic)
+	if !ok {
 		return
 	}
+	for _, e := range t.snapshot() {
+		fn(e.handler(), e.isUnique)
+	}
+}
+
+// IsPending - return whether e is registered to the topic by Once and has not been called yet
+func (b *Bus[T]) IsPending(topic string, e Event[T]) bool {
+	t, ok := b.topics.Get(topic)
+	if !ok {
+		return false
+	}
+	tag := tagOf(e)
+	for _, v := range t.snapshot() {
+		if v.tag == tag && v.isUnique && atomic.LoadUint32(&v.guard.hasCalled) == 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// Drain - remove all the topic events without calling OnStop and return them, so that they can be registered again.
+// The events registered by the wrappers like OnAsync are returned as they are passed, the goroutines of the
+// wrappers are stopped and their queued messages are dropped
+func (b *Bus[T]) Drain(topic string) []Event[T] {
+	removed := b.detach(topic, func(e *event[T]) bool {
+		return true
+	})
+	events := make([]Event[T], 0, len(removed))
+	for _, e := range removed {
+		events = append(events, e.handler())
+	}
+	return events
+}
+
+// Rename - move the events of oldTopic to newTopic, they are appended to the events of newTopic if it exists.
+// It returns whether oldTopic exists and its events are moved, they are not moved if newTopic is rejected by
+// WithMaxTopics. The events are inserted to newTopic before they are removed from oldTopic, so a concurrent
+// message reaches them by one of the topics and a once event is still called only once
+func (b *Bus[T]) Rename(oldTopic, newTopic string) bool {
+	// the renames are serialized, so that the events are never moved by two of them at the same time
+	b.renameMu.Lock()
+	defer b.renameMu.Unlock()
+	t, ok := b.topics.Get(oldTopic)
+	if !ok {
+		return false
+	}
+	if oldTopic == newTopic {
+		return true
+	}
+	events := t.snapshot()
+	for _, e := range events {
+		e.setTopic(newTopic)
+	}
+	if _, err := b.appendEvents(newTopic, events); err != nil {
+		for _, e := range events {
+			e.setTopic(oldTopic)
+		}
+		return false
+	}
+	b.take(oldTopic, func(e *event[T]) bool {
+		return slices.Contains(events, e)
+	})
+	return true
+}
+
+// Clone - return a new bus with the same events, the once events which have not been called are copied as pending.
+// The events keep their options like OnExcept, OnFallback or Suspend, and the wrappers like OnAsync or OnSerial
+// get their own goroutines on the new bus. The configuration, the history and the events owned by a Subscription
+// like OnTTL are not copied
+func (b *Bus[T]) Clone() *Bus[T] {
+	c := New[T]()
+	for _, t := range b.topicValues() {
+		events := make([]*event[T], 0, t.count())
+		for _, e := range t.snapshot() {
+			if e.owned || (e.isUnique && atomic.LoadUint32(&e.guard.hasCalled) == 1) {
+				continue
+			}
+			ev := e.cloneTo(c)
+			if ev.isUnique {
+				c.guardOnce(ev)
+			}
+			events = append(events, ev)
+		}
+		if len(events) > 0 {
+			c.insertEvents(t.name, events)
+		}
+	}
+	return c
+}
+
+// PruneEmpty - remove the topics without any event and return the number of the removed topics,
+// the topics are removed once they are empty, so it is a safety net which finds nothing usually
+func (b *Bus[T]) PruneEmpty() (n int) {
+	for _, topic := range b.topicNames() {
+		if b.topics.RemoveCb(topic, func(t *Topic[T], exists bool) bool {
+			return exists && t.count() == 0
+		}) {
+			n++
+		}
+	}
+	return
+}
+
+// BusStats struct, the snapshot of the stats returned by Stats
+type BusStats struct {
+	// Topics is the number of the topics
+	Topics int
+	// Events is the number of the events of all the topics
+	Events int
+	// Dispatches is the number of the handler calls, same as DispatchCount
+	Dispatches uint64
+	// Dropped is the number of the dropped messages, same as DroppedCount
+	Dropped uint64
+}
+
+// Stats - return the stats of the bus, the topics and their events are counted in a single pass over the topics
+func (b *Bus[T]) Stats() BusStats {
+	var stats BusStats
+	b.topics.IterCb(func(topic string, t *Topic[T]) {
+		stats.Topics++
+		stats.Events += t.count()
+	})
+	stats.Dispatches = b.dispatches.Load()
+	stats.Dropped = b.dropped.Load()
+	return stats
+}
+
+// DispatchCount - return the number of the handler calls since the bus is created or the stats are reset
+func (b *Bus[T]) DispatchCount() uint64 {
+	return b.dispatches.Load()
+}
+
+// ResetStats - reset the stats counters
+func (b *Bus[T]) ResetStats() *Bus[T] {
+	b.dispatches.Store(0)
+	b.dropped.Store(0)
+	return b
+}
+
+// Get - return the topic, an empty topic which is not stored is returned if nobody subscribed to it
+func (b *Bus[T]) Get(topic string) *Topic[T] {
+	if t, ok := b.topics.Get(topic); ok {
+		return t
+	}
+	return newTopic(b, topic)
+}
+
+// addEvents registers es and returns the number of the topic events,
+// the new topic is rejected when the number of the topics reaches the limit
+func (b *Bus[T]) addEvents(topic string, isUnique bool, es []Event[T]) (n int, err error) {
+	if len(es) == 0 {
+		return b.EventCount(topic), nil
+	}
+	events := make([]*event[T], 0, len(es))
 	for _, e := range es {
-		b.events.Upsert(topic, func(oldValue []*event[T], exist bool) []*event[T] {
-			return append(oldValue, newEvent(e, topic, isUnique))
-		})
+		ev := newEvent(e, topic, isUnique)
+		if isUnique {
+			b.guardOnce(ev)
+		}
+		events = append(events, ev)
+	}
+	return b.insertEvents(topic, events)
+}
+
+// insertEvents appends the new events to the topic and returns the number of the topic events,
+// the events rejected by appendEvents are stopped and released
+func (b *Bus[T]) insertEvents(topic string, events []*event[T]) (int, error) {
+	n, err := b.appendEvents(topic, events)
+	if err != nil {
+		for _, e := range events {
+			e.stop()
+		}
+		b.releaseOnce(events)
+	}
+	return n, err
+}
+
+// appendEvents appends events to the topic and returns the number of the topic events, nothing is inserted
+// after Shutdown, and the new topic is rejected when the number of the topics reaches the limit
+func (b *Bus[T]) appendEvents(topic string, events []*event[T]) (n int, err error) {
+	if b.closed.Load() {
+		return 0, ErrClosed
 	}
+	if b.maxTopics > 0 && !b.Exists(topic) {
+		// the new topics are created one by one, so that the limit is never exceeded
+		b.limitMu.Lock()
+		defer b.limitMu.Unlock()
+		if !b.Exists(topic) && b.topics.Count() >= b.maxTopics {
+			return 0, ErrMaxTopics
+		}
+	}
+	b.topics.Upsert(topic, func(t *Topic[T], exist bool) *Topic[T] {
+		if !exist {
+			t = newTopic(b, topic)
+		}
+		t.addEvents(events...)
+		n = t.count()
+		return t
+	})
+	b.publishMeta(MetaSub, topic, len(events))
+	b.logLifecycle("eventbus: subscribe", topic, len(events))
+	// the insertions of a topic are serialized, so only one of them crosses the threshold
+	if b.leakHandler != nil && n > b.leakThreshold && n-len(events) <= b.leakThreshold {
+		b.leakHandler(topic, n)
+	}
+	return n, nil
 }
 
 func (b *Bus[T]) removeEvents(topic string, es []Event[T]) {
-	if len(es) == 0 {
-		b.events.Remove(topic)
-		return
+	tags := make([]reflect.Value, 0, len(es))
+	for _, e := range es {
+		tags = append(tags, tagOf(e))
 	}
+	b.removeTags(topic, tags)
+}
 
-	b.events.Upsert(topic, func(oldValue []*event[T], exist bool) []*event[T] {
-		if !exist || len(oldValue) == 0 {
-			return []*event[T]{}
+// removeTags removes the events of topic whose tag is one of tags
+func (b *Bus[T]) removeTags(topic string, tags []reflect.Value) {
+	b.removeFunc(topic, func(e *event[T]) bool {
+		for _, tag := range tags {
+			if e.tag == tag {
+				return true
+			}
 		}
-		for _, e := range es {
-			tag := reflect.ValueOf(e)
-			for i, v := range oldValue {
-				if v.tag == tag {
-					oldValue = append(oldValue[:i], oldValue[i+1:]...)
-				}
+		return false
+	})
+}
+
+// clean removes all the topics and returns the events of the removed topics
+func (b *Bus[T]) clean() []*event[T] {
+	b.onceMu.Lock()
+	b.onces = make(map[reflect.Value]*onceGuard[T])
+	b.onceMu.Unlock()
+
+	var events []*event[T]
+	for _, topic := range b.topicNames() {
+		var es []*event[T]
+		b.topics.RemoveCb(topic, func(t *Topic[T], exists bool) bool {
+			if exists {
+				// the removed topic is emptied, so that a dispatch holding it finds no event
+				es = t.removeEvents(func(e *event[T]) bool {
+					return true
+				})
 			}
+			return exists
+		})
+		events = append(events, es...)
+		b.publishMeta(MetaUnsub, topic, len(es))
+		b.logLifecycle("eventbus: unsubscribe", topic, len(es))
+	}
+	for _, e := range events {
+		e.stop()
+	}
+	return events
+}
+
+// topicNames returns the names of the stored topics
+func (b *Bus[T]) topicNames() []string {
+	names := make([]string, 0, b.topics.Count())
+	b.topics.IterCb(func(topic string, _ *Topic[T]) {
+		names = append(names, topic)
+	})
+	return names
+}
+
+// topicValues returns the stored topics
+func (b *Bus[T]) topicValues() []*Topic[T] {
+	topics := make([]*Topic[T], 0, b.topics.Count())
+	b.topics.IterCb(func(_ string, t *Topic[T]) {
+		topics = append(topics, t)
+	})
+	return topics
+}
+
+// removeFunc removes the events of topic which match fn, the topic is deleted once it is empty
+func (b *Bus[T]) removeFunc(topic string, fn func(e *event[T]) bool) []*event[T] {
+	removed := b.detach(topic, fn)
+	b.stopLater(removed)
+	return removed
+}
+
+// detach removes the events of topic which match fn without calling OnStop
+func (b *Bus[T]) detach(topic string, fn func(e *event[T]) bool) []*event[T] {
+	removed := b.take(topic, fn)
+	if len(removed) > 0 {
+		for _, e := range removed {
+			e.stop()
+		}
+		b.releaseOnce(removed)
+	}
+	return removed
+}
+
+// take removes the events of topic which match fn, the topic is deleted once it is empty.
+// The timers and the once guards of the removed events are kept, so that they can be inserted to another topic
+func (b *Bus[T]) take(topic string, fn func(e *event[T]) bool) []*event[T] {
+	var removed []*event[T]
+	b.topics.RemoveCb(topic, func(t *Topic[T], exists bool) bool {
+		if !exists {
+			return false
 		}
-		return oldValue
+		removed = t.removeEvents(fn)
+		return t.count() == 0
 	})
+	b.publishMeta(MetaUnsub, topic, len(removed))
+	b.logLifecycle("eventbus: unsubscribe", topic, len(removed))
+	return removed
+}
 
-	b.events.RemoveCb(topic, func(value []*event[T], exists bool) bool {
-		return len(value) == 0
+// stopLater queues the removed events to the stop goroutine, the removals don't wait for OnStop
+func (b *Bus[T]) stopLater(es []*event[T]) {
+	if len(es) == 0 {
+		return
+	}
+	b.stopper.push(func() {
+		b.onStop(es)
 	})
 }
 
-func (b *Bus[T]) dispatch(topic string, data []T) {
+// onStop calls OnStop of the removed events, the context is limited by the stop timeout
+func (b *Bus[T]) onStop(es []*event[T]) {
+	ctx, cancel := b.stopContext(context.Background())
+	defer cancel()
+	b.onStopCtx(ctx, es)
+}
+
+// onStopCtx calls OnStop of the removed events, in reverse order when LIFOStop is set
+func (b *Bus[T]) onStopCtx(ctx context.Context, es []*event[T]) {
+	if b.lifoStop {
+		es = slices.Clone(es)
+		slices.Reverse(es)
+	}
+	for _, e := range es {
+		b.stop(ctx, e)
+	}
+	if b.batchStop == nil {
+		return
+	}
 	var (
-		removes = make(map[string][]Event[T])
+		topics  []string
+		batches = make(map[string][]Event[T])
 	)
+	for _, e := range es {
+		topic := e.topicName()
+		if _, ok := batches[topic]; !ok {
+			topics = append(topics, topic)
+		}
+		batches[topic] = append(batches[topic], e.handler())
+	}
+	for _, topic := range topics {
+		b.batchStop(topic, batches[topic])
+	}
+}
 
-	b.events.GetCb(topic, func(events []*event[T], exists bool) {
-		if !exists {
-			return
+func (b *Bus[T]) stopContext(parent context.Context) (context.Context, context.CancelFunc) {
+	if b.stopTimeout > 0 {
+		return context.WithTimeout(parent, b.stopTimeout)
+	}
+	return context.WithCancel(parent)
+}
+
+func (b *Bus[T]) stop(ctx context.Context, e *event[T]) {
+	topic := e.topicName()
+	defer b.recoverPanic(topic, e.handler())
+	callStop(ctx, e.Event, topic)
+}
+
+// guardOnce attaches e to the pending guard of its handler, a new guard is created if there
+// is none or the handler has already been called
+func (b *Bus[T]) guardOnce(e *event[T]) {
+	b.onceMu.Lock()
+	defer b.onceMu.Unlock()
+	g, ok := b.onces[e.tag]
+	if !ok || atomic.LoadUint32(&g.hasCalled) == 1 {
+		g = &onceGuard[T]{}
+		b.onces[e.tag] = g
+	}
+	g.events = append(g.events, e)
+	e.guard = g
+}
+
+// releaseOnce detaches the removed once events from their guards
+func (b *Bus[T]) releaseOnce(es []*event[T]) {
+	b.onceMu.Lock()
+	defer b.onceMu.Unlock()
+	for _, e := range es {
+		if !e.isUnique {
+			continue
 		}
-		for _, e := range events {
-			if !e.isUnique {
-				e.Dispatch(topic, data...)
-				continue
+		g := e.guard
+		for i, v := range g.events {
+			if v == e {
+				g.events = append(g.events[:i:i], g.events[i+1:]...)
+				break
+			}
+		}
+		if len(g.events) == 0 && b.onces[e.tag] == g {
+			delete(b.onces, e.tag)
+		}
+	}
+}
+
+// siblings returns the other events sharing the guard of e
+func (b *Bus[T]) siblings(e *event[T]) []*event[T] {
+	b.onceMu.Lock()
+	defer b.onceMu.Unlock()
+	var events []*event[T]
+	for _, v := range e.guard.events {
+		if v != e {
+			events = append(events, v)
+		}
+	}
+	return events
+}
+
+// dispatch calls the events of t and ALL with m. No lock is held while the handlers are called,
+// they are called over the snapshots of the topics, so a handler can trigger any topic including its own
+func (b *Bus[T]) dispatch(t *Topic[T], m *message[T]) {
+	// the messages queued before the shutdown are still delivered
+	if b.closed.Load() && !m.queued {
+		b.dropMessage(m, DropClosed)
+		return
+	}
+	// the closures are only built while the bus is paused
+	if !m.resumed && b.pause.on.Load() && b.pause.hold(func() {
+		m.resumed = true
+		b.dispatch(b.Get(m.topic), m)
+	}, func() {
+		b.dropMessage(m, DropPaused)
+	}) {
+		return
+	}
+	if b.debounced(m) {
+		return
+	}
+	epoch := b.inflight.enter()
+	defer b.inflight.exit(epoch)
+	m.seq = b.seq.Add(1)
+
+	// the topics and their batches are kept on the stack unless the topic bubbles to its ancestors
+	var buf [2]*Topic[T]
+	topics := append(buf[:0], t)
+	if b.bubbleSep != "" && !m.direct {
+		topics = append(topics, b.ancestors(m.topic)...)
+	}
+	if t.name != ALL && b.allowAsterisk && !m.direct {
+		if all, ok := b.topics.Get(ALL); ok {
+			topics = append(topics, all)
+			if b.asteriskFirst {
+				copy(topics[1:], topics[:len(topics)-1])
+				topics[0] = all
 			}
-			if atomic.CompareAndSwapUint32(&e.hasCalled, 0, 1) {
-				e.Dispatch(topic, data...)
-				removes[e.topic] = append(removes[e.topic], e.Event)
+		}
+	}
+
+	// the events of all the topics are selected first, so that the total is known before calling them
+	var cbuf [2]batch[T]
+	batches := cbuf[:0]
+	for _, t := range topics {
+		c := b.prepare(t.name, t.balance(fallbacks(b.snapshot(t, m)), m.topic), m)
+		m.total += len(c.calls)
+		batches = append(batches, c)
+	}
+	// the events of all the topics are called in a single priority order once any of them has a priority
+	merged := b.allowAsterisk && len(batches) > 1 && prioritized(batches)
+	if merged {
+		b.callMerged(batches, m)
+	}
+	n := 0
+	for i := range batches {
+		c := &batches[i]
+		var called int
+		if merged {
+			called = b.finish(c, m)
+		} else {
+			called = b.run(c, m)
+		}
+		n += called
+		if m.report != nil {
+			m.report.add(called, c.onces(), c.topic == ALL && m.topic != ALL)
+		}
+	}
+	b.publishMeta(MetaTrigger, m.topic, n)
+
+	if n == 0 && b.noListener != nil {
+		b.noListener(m.topic, m.data)
+	}
+	m.finish(n)
+}
+
+// ancestors returns the registered ancestor topics of topic from the child to the parent
+func (b *Bus[T]) ancestors(topic string) []*Topic[T] {
+	var topics []*Topic[T]
+	for i := strings.LastIndex(topic, b.bubbleSep); i > 0; i = strings.LastIndex(topic, b.bubbleSep) {
+		topic = topic[:i]
+		if t, ok := b.topics.Get(topic); ok {
+			topics = append(topics, t)
+		}
+	}
+	return topics
+}
+
+func (b *Bus[T]) broadcast(data []T, except []string, conc int, collect func(topic string, consumed []Event[T])) {
+	if b.closed.Load() {
+		b.drop(ALL, DropClosed, data)
+		return
+	}
+	if b.pause.hold(func() {
+		b.broadcastTopics(data, except, conc, collect)
+	}, func() {
+		b.drop(ALL, DropPaused, data)
+	}) {
+		return
+	}
+	b.broadcastTopics(data, except, conc, collect)
+}
+
+// broadcastTopics dispatches data to every topic except the given topics on at most conc goroutines,
+// the once events consumed by each topic are passed to collect if it is not nil
+func (b *Bus[T]) broadcastTopics(data []T, except []string, conc int, collect func(topic string, consumed []Event[T])) {
+	epoch := b.inflight.enter()
+	defer b.inflight.exit(epoch)
+	seq := b.seq.Add(1)
+	dispatch := func(t *Topic[T]) {
+		m := newMessage(t.name, data)
+		m.seq = seq
+		m.collect = collect != nil
+		b.publishMeta(MetaTrigger, t.name, b.dispatchEvents(t, m))
+		if len(m.consumed) > 0 {
+			collect(t.name, m.consumed)
+		}
+	}
+
+	var (
+		wg  sync.WaitGroup
+		sem chan struct{}
+	)
+	if conc > 1 {
+		sem = make(chan struct{}, conc)
+	}
+	for _, t := range b.topicValues() {
+		if slices.Contains(except, t.name) {
+			continue
+		}
+		if sem == nil {
+			dispatch(t)
+			continue
+		}
+		// it blocks until a goroutine is free
+		sem <- struct{}{}
+		wg.Add(1)
+		go func(t *Topic[T]) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			dispatch(t)
+		}(t)
+	}
+	wg.Wait()
+}
+
+// dispatchEvents dispatches m to the events of t and returns the number of the called events
+func (b *Bus[T]) dispatchEvents(t *Topic[T], m *message[T]) int {
+	return b.callEvents(t.name, t.balance(fallbacks(b.snapshot(t, m)), m.topic), m)
+}
+
+// callEvents dispatches m to the events of the topic and returns the number of the called events
+func (b *Bus[T]) callEvents(topic string, events []*event[T], m *message[T]) int {
+	c := b.prepare(topic, events, m)
+	m.total = len(c.calls)
+	return b.run(&c, m)
+}
+
+// batch struct, the events of a topic selected for a message
+type batch[T any] struct {
+	topic    string
+	calls    []*event[T]
+	removes  []*event[T]
+	siblings []*event[T]
+}
+
+// onces returns the number of the once events to call
+func (c *batch[T]) onces() (n int) {
+	for _, e := range c.calls {
+		if e.isUnique {
+			n++
+		}
+	}
+	return
+}
+
+// prepare selects the events to call, the once events are claimed and collected to be removed.
+// The calls share the array of events until an event is skipped, the array is never written
+func (b *Bus[T]) prepare(topic string, events []*event[T], m *message[T]) batch[T] {
+	c := batch[T]{topic: topic, calls: events}
+	skipped := false
+	for i, e := range events {
+		if !b.selects(&c, e, m) {
+			if !skipped {
+				c.calls = events[:i:i]
+				skipped = true
 			}
+			continue
 		}
-	})
+		if skipped {
+			c.calls = append(c.calls, e)
+		}
+	}
+	return c
+}
+
+// selects returns whether e is called with m, the once event is claimed and added to the removes of c
+func (b *Bus[T]) selects(c *batch[T], e *event[T], m *message[T]) bool {
+	if (b.dedupDispatch && m.called[e.tag]) || e.excluded(m.topic) || e.suspended.Load() {
+		return false
+	}
+	if e.isUnique {
+		c.removes = append(c.removes, e)
+		// the guard is shared with the once events of the same handler on other topics
+		if !atomic.CompareAndSwapUint32(&e.guard.hasCalled, 0, 1) {
+			return false
+		}
+		c.siblings = append(c.siblings, b.siblings(e)...)
+	}
+	if b.dedupDispatch {
+		m.markCalled(e.tag)
+	}
+	return true
+}
 
-	if topic != ALL {
-		b.events.GetCb(ALL, func(events []*event[T], exists bool) {
-			if !exists {
-				return
+// run calls the selected events, removes the called once events and returns the number of the called events
+func (b *Bus[T]) run(c *batch[T], m *message[T]) int {
+	n := len(c.calls)
+	var start time.Time
+	if b.timing && n > 0 {
+		start = time.Now()
+	}
+	if b.parallels.has(c.topic) {
+		var wg sync.WaitGroup
+		wg.Add(n)
+		for _, e := range c.calls {
+			go func(e *event[T]) {
+				defer wg.Done()
+				b.call(e, m)
+			}(e)
+		}
+		wg.Wait()
+	} else {
+		for _, e := range c.calls {
+			b.call(e, m)
+		}
+	}
+	if !start.IsZero() {
+		b.recordLatency(c.topic, time.Since(start))
+	}
+	return b.finish(c, m)
+}
+
+// finish counts the called events of the batch, removes the called once events and returns the number of them
+func (b *Bus[T]) finish(c *batch[T], m *message[T]) (n int) {
+	n = len(c.calls)
+	b.dispatches.Add(uint64(n))
+	if m.collect {
+		for _, e := range c.calls {
+			if e.isUnique {
+				m.consumed = append(m.consumed, e.handler())
 			}
-			for _, e := range events {
-				if !e.isUnique {
-					e.Dispatch(topic, data...)
-					continue
-				}
-				if atomic.CompareAndSwapUint32(&e.hasCalled, 0, 1) {
-					e.Dispatch(topic, data...)
-					removes[ALL] = append(removes[ALL], e.Event)
+		}
+	}
+
+	if removes := c.removes; len(removes) > 0 {
+		b.removeFunc(c.topic, func(e *event[T]) bool {
+			for _, r := range removes {
+				if e == r {
+					return true
 				}
 			}
+			return false
+		})
+	}
+	for _, s := range c.siblings {
+		b.removeFunc(s.topicName(), func(e *event[T]) bool {
+			return e == s
 		})
 	}
+	return
+}
+
+// snapshot returns the events of t, m is recorded to the history of t
+// atomically with the snapshot if it is emitted to t. The events pinned by m are returned for its topic
+// if they are still registered
+func (b *Bus[T]) snapshot(t *Topic[T], m *message[T]) []*event[T] {
+	if t.name != m.topic {
+		return t.snapshot()
+	}
+	h, ok := b.histories.get(t.name)
+	if !ok {
+		return m.registered(t.snapshot())
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.push(m.data)
+	return m.registered(b.Get(t.name).snapshot())
+}
+
+// call dispatches m to e, it stops waiting for e when the handler timeout expires
+func (b *Bus[T]) call(e *event[T], m *message[T]) {
+	if b.timeout <= 0 {
+		b.invoke(e, m)
+		return
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		b.invoke(e, m)
+	}()
+
+	timer := time.NewTimer(b.timeout)
+	defer timer.Stop()
+	select {
+	case <-done:
+	case <-timer.C:
+		if b.slowHandler != nil {
+			b.slowHandler(m.topic, e.handler(), b.timeout)
+		}
+	}
+}
 
-	for k, v := range removes {
-		b.removeEvents(k, v)
+// invoke calls Dispatch of e, the panic is recovered when the panic handler is set
+func (b *Bus[T]) invoke(e *event[T], m *message[T]) {
+	defer b.recoverPanic(m.topic, e.handler())
+	data := m.data
+	if b.copyPayload {
+		data = slices.Clone(data)
+	}
+	switch h := e.Event.(type) {
+	case *bridge[T]:
+		h.forward(m)
+	case relay[T]:
+		h.relay(m, data)
+	default:
+		b.deliver(h, m, data)
+	}
+}
+
+// deliver calls h with the message by the first of the interfaces it implements in the order ErrHandler, EventMeta,
+// EnvelopeEvent, SeqEvent, DispatchN, DispatchCoalesced and SingleEvent, the later ones are ignored. Dispatch is
+// called if it implements none of them
+func (b *Bus[T]) deliver(h Event[T], m *message[T], data []T) {
+	switch h := h.(type) {
+	case ErrHandler[T]:
+		if err := h.ErrEvent.Dispatch(m.topic, data...); err != nil {
+			b.reportError(m.topic, h.ErrEvent, err)
+		}
+	case EventMeta[T]:
+		h.DispatchMeta(m.meta, m.topic, data)
+	case EnvelopeEvent[T]:
+		h.DispatchEnvelope(m.envelope(data))
+	case SeqEvent[T]:
+		h.DispatchSeq(m.seq, m.topic, data)
+	case DispatchN[T]:
+		h.DispatchN(m.topic, data, m.total)
+	case DispatchCoalesced[T]:
+		h.DispatchCoalesced(m.topic, data, max(m.coalesced, 1))
+	case SingleEvent[T]:
+		for _, v := range data {
+			h.DispatchOne(m.topic, v)
+		}
+	default:
+		h.Dispatch(m.topic, data...)
+	}
+}
+
+// recoverPanic passes the recovered panic to the panic handler, it must be deferred
+func (b *Bus[T]) recoverPanic(topic string, e Event[T]) {
+	if b.panicHandler == nil {
+		return
+	}
+	if v := recover(); v != nil {
+		b.logError("eventbus: panic", topic, e, slog.Any("panic", v))
+		b.panicHandler(topic, e, v)
 	}
 }
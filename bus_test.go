@@ -1,13 +1,16 @@
 package eventbus
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"math/rand"
 	"runtime"
+	"strconv"
 	"sync"
 	"sync/atomic"
 	"testing"
+	"time"
 )
 
 type N struct {
@@ -54,151 +57,2077 @@ func TestOn(t *testing.T) {
 	}
 }
 
+func TestOnMultiple(t *testing.T) {
+	o := New[string]()
+	n := 0
+
+	e1, e2, e3 := &N{&n, ""}, &N{&n, ""}, &N{&n, ""}
+	o.On("foo", e1, e2, e3).Trigger("foo", "1")
+	if n != 3 {
+		t.Errorf("The counter is %d instead of being %d", n, 3)
+	}
+
+	o.Off("foo", e1, e3).Trigger("foo", "2")
+	if n != 4 {
+		t.Errorf("The counter is %d instead of being %d", n, 4)
+	}
+	if e1.s != "1" || e2.s != "2" || e3.s != "1" {
+		t.Errorf("The messages are %s, %s and %s instead of being %s, %s and %s", e1.s, e2.s, e3.s, "1", "2", "1")
+	}
+}
+
+func TestOnCount(t *testing.T) {
+	o := New[string]()
+	n := 0
+
+	for i := 1; i <= 3; i++ {
+		if c := o.OnCount("foo", &N{&n, ""}); c != i {
+			t.Errorf("The event count is %d instead of being %d", c, i)
+		}
+	}
+	if c := o.OnCount("bar", &N{&n, ""}); c != 1 {
+		t.Errorf("The event count is %d instead of being %d", c, 1)
+	}
+	if c := o.EventCount("foo"); c != 3 {
+		t.Errorf("The event count is %d instead of being %d", c, 3)
+	}
+}
+
+func TestOnMany(t *testing.T) {
+	o := New[string]()
+	n := 0
+
+	e := &N{&n, ""}
+	o.OnMany([]string{"foo", "bar", "baz"}, e)
+	o.Trigger("foo", "test1").Trigger("bar", "test2").Trigger("baz", "test3")
+	if n != 3 {
+		t.Errorf("The counter is %d instead of being %d", n, 3)
+	}
+	if e.s != "test3" {
+		t.Errorf("The last event name triggered is %s instead of being %s", e.s, "test3")
+	}
+
+	o.OffMany([]string{"foo", "bar"}, e)
+	o.Trigger("foo", "test4").Trigger("bar", "test5").Trigger("baz", "test6")
+	if n != 4 {
+		t.Errorf("The counter is %d instead of being %d", n, 4)
+	}
+	if o.Exists("foo") || o.Exists("bar") {
+		t.Error("The topics foo and bar should be removed")
+	}
+}
+
+func TestHas(t *testing.T) {
+	o := New[string]()
+	n := 0
+
+	onFoo := &N{&n, ""}
+	other := &N{&n, ""}
+	o.On("foo", onFoo)
+
+	if !o.Has("foo", onFoo) {
+		t.Error("The event should be registered to foo")
+	}
+	if o.Has("foo", other) {
+		t.Error("The other event should not be registered to foo")
+	}
+	if o.Has("bar", onFoo) {
+		t.Error("The event should not be registered to bar")
+	}
+}
+
 func TestOnAll(t *testing.T) {
 	o := New[string]()
 	n := 0
 
-	onAll := &N{&n, ""}
-
-	o.On(ALL, onAll)
+	onAll := &N{&n, ""}
+
+	o.On(ALL, onAll)
+
+	o.Trigger("foo", "foo").Trigger("bar", "bar")
+
+	o.Off(ALL, onAll)
+
+	o.Trigger("bar", "bar").Trigger("foo", "bar")
+
+	if onAll.s != "bar" {
+		t.Errorf("The last event name triggered is %s instead of being %s", onAll.s, "bar")
+	}
+
+	if n != 2 {
+		t.Errorf("The counter is %d instead of being %d", n, 2)
+	}
+
+}
+
+func TestClean(t *testing.T) {
+	o := New[string]()
+	n := 0
+
+	fn := &N{&n, ""}
+	o.On("foo", fn)
+
+	o.On("bar", fn)
+
+	o.Clean()
+
+	o.Trigger("foo").Trigger("bar").Trigger("foo bar")
+
+	if n != 0 {
+		t.Errorf("The counter is %d instead of being %d", n, 0)
+	}
+}
+
+type stopEvent struct {
+	stops *int64
+}
+
+func (e *stopEvent) Dispatch(topic string, data ...string) {}
+
+func (e *stopEvent) OnStop(topic string) {
+	atomic.AddInt64(e.stops, 1)
+}
+
+func TestCleanSync(t *testing.T) {
+	o := New[string]()
+	var stops int64
+
+	o.On("foo", &stopEvent{&stops}, &stopEvent{&stops}).Once("bar", &stopEvent{&stops})
+	o.CleanSync()
+
+	if stops != 3 {
+		t.Errorf("The stop counter is %d instead of being %d", stops, 3)
+	}
+	if c := o.EventCount("foo"); c != 0 {
+		t.Errorf("The event count is %d instead of being %d", c, 0)
+	}
+}
+
+type slowStopEvent struct {
+	stops *int64
+}
+
+func (e *slowStopEvent) Dispatch(topic string, data ...string) {}
+
+func (e *slowStopEvent) OnStop(topic string) {
+	time.Sleep(time.Millisecond)
+	atomic.AddInt64(e.stops, 1)
+}
+
+func TestStopChurn(t *testing.T) {
+	o := New[string]()
+	var stops int64
+
+	// the removals don't start a goroutine each while OnStop is slow
+	const n = 200
+	base := runtime.NumGoroutine()
+	peak := base
+	for i := 0; i < n; i++ {
+		e := &slowStopEvent{&stops}
+		o.On("foo", e).Off("foo", e)
+		peak = max(peak, runtime.NumGoroutine())
+	}
+	if peak-base > 2 {
+		t.Errorf("The goroutine count grows by %d during the churn", peak-base)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for atomic.LoadInt64(&stops) != n && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if c := atomic.LoadInt64(&stops); c != n {
+		t.Errorf("The stop counter is %d instead of being %d", c, n)
+	}
+}
+
+type orderStopEvent struct {
+	id    int
+	stops *[]int
+}
+
+func (e *orderStopEvent) Dispatch(topic string, data ...string) {}
+
+func (e *orderStopEvent) OnStop(topic string) {
+	*e.stops = append(*e.stops, e.id)
+	if e.id == 0 {
+		panic("stop")
+	}
+}
+
+type ctxStopEvent struct {
+	deadlines []time.Time
+	stops     int
+}
+
+func (e *ctxStopEvent) Dispatch(topic string, data ...string) {}
+
+func (e *ctxStopEvent) OnStop(topic string) {
+	e.stops++
+}
+
+func (e *ctxStopEvent) OnStopCtx(ctx context.Context, topic string) {
+	deadline, _ := ctx.Deadline()
+	e.deadlines = append(e.deadlines, deadline)
+}
+
+func TestClose(t *testing.T) {
+	o := New[string]()
+
+	onFoo := &ctxStopEvent{}
+	o.On("foo", onFoo).On("bar", onFoo)
+
+	deadline := time.Now().Add(time.Minute)
+	ctx, cancel := context.WithDeadline(context.Background(), deadline)
+	defer cancel()
+	if err := o.Close(ctx); err != nil {
+		t.Error(err)
+	}
+	if len(onFoo.deadlines) != 2 || !onFoo.deadlines[0].Equal(deadline) || !onFoo.deadlines[1].Equal(deadline) {
+		t.Errorf("The deadlines are %v instead of being %v", onFoo.deadlines, deadline)
+	}
+	if onFoo.stops != 0 {
+		t.Errorf("The OnStop counter is %d instead of being %d", onFoo.stops, 0)
+	}
+
+	// the stop timeout limits the context of CleanSync
+	onBar := &ctxStopEvent{}
+	o.WithStopTimeout(time.Second).On("bar", onBar).CleanSync()
+	if len(onBar.deadlines) != 1 || onBar.deadlines[0].IsZero() {
+		t.Errorf("The deadlines are %v instead of being set", onBar.deadlines)
+	}
+
+	// Close returns the error of the done context
+	ctx, cancel = context.WithCancel(context.Background())
+	cancel()
+	if err := o.Close(ctx); err != context.Canceled {
+		t.Errorf("The error is %v instead of being %v", err, context.Canceled)
+	}
+}
+
+func TestLIFOStop(t *testing.T) {
+	o := New[string]()
+	stops := []int{}
+	panics := []any{}
+
+	o.LIFOStop().OnPanic(func(topic string, e Event[string], v any) {
+		panics = append(panics, v)
+	})
+	for i := 0; i < 3; i++ {
+		o.On("foo", &orderStopEvent{i, &stops})
+	}
+	o.CleanSync()
+
+	if fmt.Sprint(stops) != fmt.Sprint([]int{2, 1, 0}) {
+		t.Errorf("The stop order is %v instead of being %v", stops, []int{2, 1, 0})
+	}
+	if len(panics) != 1 || panics[0] != "stop" {
+		t.Errorf("The panics are %v instead of being %v", panics, []any{"stop"})
+	}
+}
+
+func TestDrain(t *testing.T) {
+	o := New[string]()
+	var stops int64
+	n := 0
+
+	keep1, keep2 := &N{&n, ""}, &N{&n, ""}
+	o.On("foo", keep1, &stopEvent{&stops}, keep2)
+
+	var events []Event[string]
+	for _, e := range o.Get("foo").Drain() {
+		if _, ok := e.(*stopEvent); !ok {
+			events = append(events, e)
+		}
+	}
+	if c := o.EventCount("foo"); c != 0 {
+		t.Errorf("The event count is %d instead of being %d", c, 0)
+	}
+	if len(events) != 2 || events[0] != keep1 || events[1] != keep2 {
+		t.Errorf("The drained events are %v instead of being %v", events, []Event[string]{keep1, keep2})
+	}
+
+	o.On("foo", events...).Trigger("foo", "1")
+	if n != 2 {
+		t.Errorf("The counter is %d instead of being %d", n, 2)
+	}
+
+	if atomic.LoadInt64(&stops) != 0 {
+		t.Errorf("The stop counter is %d instead of being %d", stops, 0)
+	}
+
+	// Clear calls OnStop
+	stopped := &ttlEvent{stopped: make(chan string, 1)}
+	o.On("foo", stopped).Get("foo").Clear()
+	select {
+	case <-stopped.stopped:
+	case <-time.After(time.Second):
+		t.Error("The cleared event is not stopped")
+	}
+	if c := o.EventCount("foo"); c != 0 {
+		t.Errorf("The event count is %d instead of being %d", c, 0)
+	}
+}
+
+func TestOff(t *testing.T) {
+	o := New[string]()
+	n := 0
+
+	onFoo1 := &N{&n, ""}
+
+	onFoo2 := &N{&n, ""}
+
+	o.On("foo", onFoo1).On("foo", onFoo2)
+	o.Trigger("foo", "test1")
+	if onFoo1.s != "test1" {
+		t.Fail()
+	}
+	if onFoo2.s != "test1" {
+		t.Fail()
+	}
+
+	o.Off("foo", onFoo1).Off("foo", onFoo2).On("foo", onFoo1)
+	o.Trigger("foo", "test2")
+	if onFoo1.s != "test2" {
+		t.Fail()
+	}
+	if onFoo2.s == "test2" {
+		t.Fail()
+	}
+
+	o.On("foo", onFoo2).Off("foo", onFoo1)
+	o.Trigger("foo", "test3")
+	if onFoo1.s == "test3" {
+		t.Fail()
+	}
+	if onFoo2.s != "test3" {
+		t.Fail()
+	}
+
+	if n != 4 {
+		t.Errorf("The counter is %d instead of being %d", n, 3)
+	}
+
+}
+
+func TestEachEvent(t *testing.T) {
+	o := New[string]()
+	n := 0
+
+	e := &N{&n, ""}
+	o.On("foo", e, &N{&n, ""}).Once("foo", &N{&n, ""}).OnReplay("foo", &N{&n, ""})
+	once, persistent := 0, 0
+	o.EachEvent("foo", func(v Event[string], isOnce bool) {
+		if _, ok := v.(*N); !ok {
+			t.Errorf("The event is %T instead of being %T", v, e)
+		}
+		if isOnce {
+			once++
+		} else {
+			persistent++
+		}
+		// the events can be removed while iterating
+		o.Off("foo", e)
+	})
+	if once != 1 || persistent != 3 {
+		t.Errorf("The counters are %d and %d instead of being %d and %d", once, persistent, 1, 3)
+	}
+	if c := o.EventCount("foo"); c != 3 || n != 0 {
+		t.Errorf("The event count is %d instead of being %d", c, 3)
+	}
+
+	o.EachEvent("bar", func(v Event[string], isOnce bool) {
+		t.Error("The topic bar has no event")
+	})
+}
+
+func TestIsPending(t *testing.T) {
+	o := New[string]()
+	n := 0
+
+	e := &N{&n, ""}
+	o.Once("foo", e).On("bar", e)
+	if !o.IsPending("foo", e) {
+		t.Error("The once event should be pending")
+	}
+	if o.IsPending("bar", e) || o.IsPending("baz", e) {
+		t.Error("The event which is not registered by Once should not be pending")
+	}
+
+	o.Trigger("foo")
+	if o.IsPending("foo", e) {
+		t.Error("The called once event should not be pending")
+	}
+	if o.Has("foo", e) {
+		t.Error("The called once event should be removed")
+	}
+}
+
+func TestExists(t *testing.T) {
+	o := New[string]()
+	n := 0
+
+	if o.Exists("foo") {
+		t.Error("The topic foo should not exist")
+	}
+	o.On("foo", &N{&n, ""})
+	if !o.Exists("foo") {
+		t.Error("The topic foo should exist")
+	}
+
+	// the topic stored without any event exists while its count is 0
+	o.insertEvents("bar", nil)
+	if !o.Exists("bar") || o.EventCount("bar") != 0 {
+		t.Error("The topic bar should exist without any event")
+	}
+	if o.Exists("baz") || o.EventCount("baz") != 0 {
+		t.Error("The topic baz should not exist")
+	}
+
+	o.Off("foo")
+	if o.Exists("foo") {
+		t.Error("The topic foo should be removed")
+	}
+	o.PruneEmpty()
+	if o.Exists("bar") {
+		t.Error("The topic bar should be pruned")
+	}
+}
+
+func TestTryOnEmptyTopic(t *testing.T) {
+	o := New[string]()
+	n := 0
+
+	if err := o.TryOn("", &N{&n, ""}); err != ErrEmptyTopic {
+		t.Errorf("The error is %v instead of being %v", err, ErrEmptyTopic)
+	}
+	if o.Exists("") {
+		t.Error("The empty topic should not be subscribed by TryOn")
+	}
+
+	// On still accepts the empty topic
+	o.On("", &N{&n, ""}).Trigger("")
+	if n != 1 {
+		t.Errorf("The counter is %d instead of being %d", n, 1)
+	}
+}
+
+func TestWithMaxTopics(t *testing.T) {
+	o := New[string]().WithMaxTopics(2)
+	n := 0
+
+	if err := o.TryOn("foo", &N{&n, ""}); err != nil {
+		t.Errorf("The error is %v instead of being nil", err)
+	}
+	o.On("bar", &N{&n, ""})
+	if err := o.TryOn("baz", &N{&n, ""}); err != ErrMaxTopics {
+		t.Errorf("The error is %v instead of being %v", err, ErrMaxTopics)
+	}
+	o.On("baz", &N{&n, ""}).Once("baz", &N{&n, ""})
+	if c := o.TopicCount(); c != 2 || o.Exists("baz") {
+		t.Errorf("The topic count is %d instead of being %d", c, 2)
+	}
+
+	// the existing topics keep working
+	if err := o.TryOn("foo", &N{&n, ""}); err != nil {
+		t.Errorf("The error is %v instead of being nil", err)
+	}
+	if c := o.OnCount("bar", &N{&n, ""}); c != 2 {
+		t.Errorf("The event count is %d instead of being %d", c, 2)
+	}
+	o.Trigger("foo").Trigger("bar").Trigger("baz")
+	if n != 4 {
+		t.Errorf("The counter is %d instead of being %d", n, 4)
+	}
+
+	// the removed topic frees the slot
+	o.Off("foo")
+	if err := o.TryOn("baz", &N{&n, ""}); err != nil {
+		t.Errorf("The error is %v instead of being nil", err)
+	}
+
+	// the wrappers and the other registrations are limited too
+	o.OnAsync("a", 1, &N{&n, ""}).OnSerial("b", &N{&n, ""}).OnGroup("c", "g", &N{&n, ""}).OnFallback("d", &N{&n, ""})
+	o.OnTTL("e", time.Hour, &N{&n, ""})
+	if c := o.TopicCount(); c != 2 {
+		t.Errorf("The topic count is %d instead of being %d", c, 2)
+	}
+	if o.Rename("bar", "f") || !o.Exists("bar") || o.EventCount("bar") != 2 {
+		t.Error("The rename to a new topic should be rejected")
+	}
+}
+
+func TestOffWithoutEvents(t *testing.T) {
+	o := New[string]()
+	n := 0
+
+	stopped := &ttlEvent{stopped: make(chan string, 1)}
+	o.On("foo", stopped, &N{&n, ""}).On("bar", &N{&n, ""})
+
+	// Off without events removes all the topic events and calls OnStop
+	o.Off("foo")
+	select {
+	case <-stopped.stopped:
+	case <-time.After(time.Second):
+		t.Error("The removed event is not stopped")
+	}
+	if o.Exists("foo") {
+		t.Error("The topic foo should be removed")
+	}
+	if c := o.EventCount("bar"); c != 1 {
+		t.Errorf("The event count is %d instead of being %d", c, 1)
+	}
+
+	// the unknown events or topics are ignored
+	o.Off("bar", &N{&n, ""}).Off("baz")
+	if c := o.EventCount("bar"); c != 1 {
+		t.Errorf("The event count is %d instead of being %d", c, 1)
+	}
+}
+
+func TestOnBatchStop(t *testing.T) {
+	o := New[string]()
+	n := 0
+	batches := make(chan []Event[string], 3)
+	topics := make(chan string, 3)
+
+	o.OnBatchStop(func(topic string, handlers []Event[string]) {
+		topics <- topic
+		batches <- handlers
+	})
+	e1, e2, e3 := &N{&n, ""}, &N{&n, ""}, &N{&n, ""}
+	o.On("foo", e1, e2, e3)
+
+	o.Off("foo", e1, e3)
+	select {
+	case batch := <-batches:
+		if topic := <-topics; topic != "foo" {
+			t.Errorf("The batch topic is %s instead of being %s", topic, "foo")
+		}
+		if len(batch) != 2 || batch[0] != e1 || batch[1] != e3 {
+			t.Errorf("The batch is %v instead of being %v", batch, []Event[string]{e1, e3})
+		}
+	case <-time.After(time.Second):
+		t.Fatal("The batch callback is not called")
+	}
+
+	// the removed events of every topic are batched separately
+	o.On("bar", e1).CleanSync()
+	got := map[string]int{}
+	for i := 0; i < 2; i++ {
+		got[<-topics] = len(<-batches)
+	}
+	if got["foo"] != 1 || got["bar"] != 1 {
+		t.Errorf("The batches are %v instead of being %v", got, map[string]int{"foo": 1, "bar": 1})
+	}
+}
+
+func TestOffAll(t *testing.T) {
+	o := New[string]()
+	var stops int64
+
+	o.On("foo", &stopEvent{&stops}, &stopEvent{&stops})
+	o.Off("foo")
+	if c := o.EventCount("foo"); c != 0 {
+		t.Errorf("The event count is %d instead of being %d", c, 0)
+	}
+
+	o.On("foo", &stopEvent{&stops}, &stopEvent{&stops}, &stopEvent{&stops})
+	if c := o.OffAll("foo"); c != 3 {
+		t.Errorf("The removed count is %d instead of being %d", c, 3)
+	}
+	if o.Exists("foo") {
+		t.Error("The topic foo should be removed")
+	}
+	if c := o.OffAll("foo"); c != 0 {
+		t.Errorf("The removed count is %d instead of being %d", c, 0)
+	}
+}
+
+func TestOffFunc(t *testing.T) {
+	o := New[string]()
+	n := 0
+	var stops int64
+
+	o.On("foo", &N{&n, "a"}, &stopEvent{&stops}, &N{&n, "b"}, &stopEvent{&stops})
+	c := o.OffFunc("foo", func(e Event[string]) bool {
+		_, ok := e.(*stopEvent)
+		return ok
+	})
+	if c != 2 {
+		t.Errorf("The removed count is %d instead of being %d", c, 2)
+	}
+	c = o.OffFunc("foo", func(e Event[string]) bool {
+		v, ok := e.(*N)
+		return ok && v.s == "a"
+	})
+	if c != 1 {
+		t.Errorf("The removed count is %d instead of being %d", c, 1)
+	}
+
+	o.Trigger("foo", "test")
+	if n != 1 {
+		t.Errorf("The counter is %d instead of being %d", n, 1)
+	}
+	if c := o.OffFunc("bar", func(e Event[string]) bool { return true }); c != 0 {
+		t.Errorf("The removed count is %d instead of being %d", c, 0)
+	}
+}
+
+func TestSetOnce(t *testing.T) {
+	o := New[string]()
+	n := 0
+
+	e1, e2, e3 := &N{&n, ""}, &N{&n, ""}, &N{&n, ""}
+	o.On("foo", e1).Once("foo", e2).On("foo", e3).Once("bar", e2)
+	if !o.SetOnce("foo", e2, false) {
+		t.Error("The once event should be found")
+	}
+	o.Trigger("foo", "test1").Trigger("foo", "test2").Trigger("foo", "test3")
+	if n != 9 {
+		t.Errorf("The counter is %d instead of being %d", n, 9)
+	}
+
+	// the order is kept
+	var events []Event[string]
+	o.EachEvent("foo", func(e Event[string], isOnce bool) {
+		events = append(events, e)
+		if isOnce {
+			t.Error("The events of foo should be persistent")
+		}
+	})
+	if len(events) != 3 || events[0] != e1 || events[1] != e2 || events[2] != e3 {
+		t.Errorf("The events are %v instead of being %v", events, []Event[string]{e1, e2, e3})
+	}
+
+	// the once event of the other topic is still a once event
+	o.Trigger("bar", "test4").Trigger("bar", "test5")
+	if n != 10 {
+		t.Errorf("The counter is %d instead of being %d", n, 10)
+	}
+	if o.SetOnce("bar", e2, false) {
+		t.Error("The consumed once event should not be found")
+	}
+
+	if !o.SetOnce("foo", e3, true) {
+		t.Error("The persistent event should be found")
+	}
+	o.Trigger("foo", "test6").Trigger("foo", "test7")
+	if n != 15 {
+		t.Errorf("The counter is %d instead of being %d", n, 15)
+	}
+	if o.Has("foo", e3) {
+		t.Error("The called once event should be removed")
+	}
+	if o.SetOnce("foo", e3, false) || o.SetOnce("baz", e1, true) {
+		t.Error("The event should not be found")
+	}
+}
+
+func TestSuspend(t *testing.T) {
+	o := New[string]()
+	n := 0
+
+	e1, e2, e3 := &N{&n, ""}, &N{&n, ""}, &N{&n, ""}
+	once := &N{&n, ""}
+	o.On("foo", e1, e2, e3).Once("foo", once)
+	o.Suspend("foo", e2).Suspend("foo", once).Trigger("foo", "test1")
+	if n != 2 {
+		t.Errorf("The counter is %d instead of being %d", n, 2)
+	}
+	if e1.s != "test1" || e3.s != "test1" || e2.s != "" || once.s != "" {
+		t.Error("Only the events which are not suspended should be called")
+	}
+	if !o.IsPending("foo", once) {
+		t.Error("The suspended once event should not be consumed")
+	}
+
+	o.Unsuspend("foo", e2).Unsuspend("foo", once).Trigger("foo", "test2")
+	if n != 6 {
+		t.Errorf("The counter is %d instead of being %d", n, 6)
+	}
+	if c := o.EventCount("foo"); c != 3 {
+		t.Errorf("The event count is %d instead of being %d", c, 3)
+	}
+}
+
+func TestOffIndex(t *testing.T) {
+	o := New[string]()
+	var stops int64
+
+	e1, e2, e3 := &stopEvent{&stops}, &stopEvent{&stops}, &stopEvent{&stops}
+	o.On("foo", e1, e2, e3)
+	if e, ok := o.OffIndex("foo", 1); !ok || e != e2 {
+		t.Errorf("The removed event is %p instead of being %p", e, e2)
+	}
+
+	var rest []Event[string]
+	o.EachEvent("foo", func(e Event[string], isOnce bool) {
+		rest = append(rest, e)
+	})
+	if len(rest) != 2 || rest[0] != e1 || rest[1] != e3 {
+		t.Errorf("The events are %v instead of being %v", rest, []Event[string]{e1, e3})
+	}
+
+	for _, i := range []int{-1, 2} {
+		if _, ok := o.OffIndex("foo", i); ok {
+			t.Errorf("The index %d should be out of range", i)
+		}
+	}
+	if _, ok := o.OffIndex("bar", 0); ok {
+		t.Error("The topic bar should not exist")
+	}
+
+	for i := 0; i < 100 && atomic.LoadInt64(&stops) == 0; i++ {
+		time.Sleep(time.Millisecond)
+	}
+	if n := atomic.LoadInt64(&stops); n != 1 {
+		t.Errorf("The stop counter is %d instead of being %d", n, 1)
+	}
+}
+
+type recursiveEvent struct {
+	bus    *Bus[string]
+	depths []string
+}
+
+func (e *recursiveEvent) Dispatch(topic string, data ...string) {
+	e.depths = append(e.depths, data[0])
+	if depth, _ := strconv.Atoi(data[0]); depth > 0 {
+		e.bus.Trigger(topic, strconv.Itoa(depth-1))
+		// the other topics of the same bus are reachable too
+		e.bus.Trigger("bar", data[0])
+	}
+}
+
+func TestRecursiveTrigger(t *testing.T) {
+	o := New[string]()
+	n := 0
+
+	e := &recursiveEvent{bus: o}
+	o.On("foo", e, &N{&n, ""}).On("bar", &N{&n, ""})
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		o.Trigger("foo", "3")
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("The recursive trigger is deadlocked")
+	}
+
+	// the nested messages are dispatched before the outer dispatch moves on
+	if s := fmt.Sprint(e.depths); s != "[3 2 1 0]" {
+		t.Errorf("The depths are %s instead of being %s", s, "[3 2 1 0]")
+	}
+	if n != 7 {
+		t.Errorf("The counter is %d instead of being %d", n, 7)
+	}
+}
+
+type offEvent struct {
+	bus  *Bus[string]
+	offs []Event[string]
+	i    *int
+}
+
+func (e *offEvent) Dispatch(topic string, data ...string) {
+	*e.i++
+	e.bus.Off(topic, e.offs...)
+}
+
+func TestOffInDispatch(t *testing.T) {
+	o := New[string]()
+	n := 0
+
+	other := &N{&n, ""}
+	self := &offEvent{bus: o, i: &n}
+	self.offs = []Event[string]{self, other}
+	o.On("foo", self, other)
+
+	// the removal takes effect after the current dispatch
+	o.Trigger("foo", "test1")
+	if n != 2 {
+		t.Errorf("The counter is %d instead of being %d", n, 2)
+	}
+	if other.s != "test1" {
+		t.Errorf("The last event name triggered is %s instead of being %s", other.s, "test1")
+	}
+
+	o.Trigger("foo", "test2")
+	if n != 2 {
+		t.Errorf("The counter is %d instead of being %d", n, 2)
+	}
+	if o.Exists("foo") {
+		t.Error("The topic foo should be removed")
+	}
+}
+
+func TestRace(t *testing.T) {
+	o := New[string]()
+	n := 0
+
+	asyncTask := func(wg *sync.WaitGroup) {
+		o.Trigger("foo")
+		wg.Done()
+	}
+	var wg sync.WaitGroup
+
+	wg.Add(5)
+
+	fn := &N{&n, ""}
+	o.On("foo", fn)
+
+	go asyncTask(&wg)
+	go asyncTask(&wg)
+	go asyncTask(&wg)
+	go asyncTask(&wg)
+	go asyncTask(&wg)
+
+	wg.Wait()
+
+	if n != 5 {
+		t.Errorf("The counter is %d instead of being %d", n, 5)
+	}
+
+}
+
+func TestOne(t *testing.T) {
+	o := New[string]()
+	n := 0
+
+	onFoo := &N{&n, ""}
+
+	o.Once("foo", onFoo)
+
+	o.Trigger("foo").Trigger("foo").Trigger("foo")
+
+	if n != 1 {
+		t.Errorf("The counter is %d instead of being %d", n, 1)
+	}
+
+}
+
+func TestPruneEmpty(t *testing.T) {
+	o := New[string]()
+	n := 0
+
+	o.Once("foo", &N{&n, ""}).Trigger("foo")
+	o.Once("bar", &N{&n, ""}).Get("bar").Dispatch()
+	o.Once(ALL, &N{&n, ""}).Trigger("baz")
+	e := &N{&n, ""}
+	o.On("qux", e).Off("qux", e)
+
+	if c := o.topics.Count(); c != 0 {
+		t.Errorf("The topic count is %d instead of being %d", c, 0)
+	}
+	if c := o.PruneEmpty(); c != 0 {
+		t.Errorf("The pruned count is %d instead of being %d", c, 0)
+	}
+
+	o.On("foo", e)
+	o.topics.Upsert("bar", func(_ *Topic[string], _ bool) *Topic[string] {
+		return newTopic(o, "bar")
+	})
+	if c := o.PruneEmpty(); c != 1 {
+		t.Errorf("The pruned count is %d instead of being %d", c, 1)
+	}
+	if !o.Exists("foo") || o.Exists("bar") {
+		t.Error("Only the empty topic should be pruned")
+	}
+}
+
+func TestOnceRace(t *testing.T) {
+	for i := 0; i < 100; i++ {
+		o := New[string]()
+		var counter int64
+
+		onFoo := &benchmarkEvent{&counter}
+		o.Once("foo", onFoo).Once(ALL, onFoo)
+
+		var wg sync.WaitGroup
+		wg.Add(10)
+		for j := 0; j < 5; j++ {
+			go func() {
+				defer wg.Done()
+				o.Trigger("foo")
+			}()
+			go func() {
+				defer wg.Done()
+				o.Broadcast()
+			}()
+		}
+		wg.Wait()
+
+		if counter != 1 {
+			t.Fatalf("The counter is %d instead of being %d", counter, 1)
+		}
+		if o.topics.Count() != 0 {
+			t.Fatalf("The topic count is %d instead of being %d", o.topics.Count(), 0)
+		}
+	}
+}
+
+type blockEvent struct {
+	entered chan struct{}
+	release chan struct{}
+}
+
+func (e *blockEvent) Dispatch(topic string, data ...string) {
+	close(e.entered)
+	<-e.release
+}
+
+func TestDispatchWithoutLock(t *testing.T) {
+	o := New[string]()
+	n := 0
+
+	e := &blockEvent{make(chan struct{}), make(chan struct{})}
+	o.On("foo", e)
+	go o.Trigger("foo")
+	<-e.entered
+
+	// the topic is not locked while the handler is running
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		other := &N{&n, ""}
+		o.On("foo", other).Off("foo", other)
+		o.EventCount("foo")
+		o.On("bar", other).Trigger("bar")
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("The topic is locked by the running handler")
+	}
+	close(e.release)
+}
+
+func TestOnceRemovalRace(t *testing.T) {
+	o := New[string]()
+	var counter int64
+	n := 0
+
+	const onces = 100
+	o.On("foo", &N{&n, ""})
+	for i := 0; i < onces; i++ {
+		o.Once("foo", &benchmarkEvent{&counter})
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			o.Trigger("foo")
+		}()
+		go func() {
+			defer wg.Done()
+			e := &benchmarkEvent{new(int64)}
+			o.On("foo", e).Off("foo", e)
+		}()
+	}
+	wg.Wait()
+
+	// every once event is called and removed once, whichever dispatch claimed it
+	if counter != onces {
+		t.Errorf("The counter is %d instead of being %d", counter, onces)
+	}
+	if c := o.EventCount("foo"); c != 1 {
+		t.Errorf("The event count is %d instead of being %d", c, 1)
+	}
+}
+
+func TestArguments(t *testing.T) {
+	o := New[input]()
+	n := 0
+	fn := &FN{&n}
+	o.On("foo", fn)
+
+	o.Trigger("foo", input{"bar", true})
+
+	if n != 1 {
+		t.Errorf("The counter is %d instead of being %d", n, 1)
+	}
+}
+
+func TestTrigger(t *testing.T) {
+	o := New[interface{}]()
+	// the trigger without any listener should not throw errors
+	o.Trigger("foo")
+}
+
+type topicEvent struct {
+	n     int
+	topic string
+}
+
+func (e *topicEvent) Dispatch(topic string, data ...string) {
+	e.n++
+	e.topic = topic
+}
+
+func TestFanOut(t *testing.T) {
+	cases := []struct {
+		name     string
+		asterisk bool
+		fire     func(o *Bus[string])
+		foo      int
+		all      int
+		topic    string
+	}{
+		{"Emit topic with asterisk", true, func(o *Bus[string]) { o.Emit("foo", "1") }, 1, 1, "foo"},
+		{"Emit topic without asterisk", false, func(o *Bus[string]) { o.Emit("foo", "1") }, 1, 0, ""},
+		{"Emit ALL with asterisk", true, func(o *Bus[string]) { o.Emit(ALL, "1") }, 0, 1, ALL},
+		{"Emit ALL without asterisk", false, func(o *Bus[string]) { o.Emit(ALL, "1") }, 0, 1, ALL},
+		{"Broadcast with asterisk", true, func(o *Bus[string]) { o.Broadcast("1") }, 1, 1, ALL},
+		{"Broadcast without asterisk", false, func(o *Bus[string]) { o.Broadcast("1") }, 1, 1, ALL},
+		{"Broadcast skipping ALL", true, func(o *Bus[string]) { o.BroadcastSkipAll().Broadcast("1") }, 1, 0, ""},
+		{"Broadcast except ALL", true, func(o *Bus[string]) { o.BroadcastExcept([]string{ALL}, "1") }, 1, 0, ""},
+		{"Broadcast except foo", true, func(o *Bus[string]) { o.BroadcastExcept([]string{"foo", "bar"}, "1") }, 0, 1, ALL},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			o := New[string]().AllowAsterisk(c.asterisk)
+			onFoo := &topicEvent{}
+			onAll := &topicEvent{}
+			o.On("foo", onFoo).On(ALL, onAll)
+
+			c.fire(o)
+
+			if onFoo.n != c.foo {
+				t.Errorf("The foo counter is %d instead of being %d", onFoo.n, c.foo)
+			}
+			if onAll.n != c.all {
+				t.Errorf("The ALL counter is %d instead of being %d", onAll.n, c.all)
+			}
+			if onAll.topic != c.topic {
+				t.Errorf("The ALL topic is %s instead of being %s", onAll.topic, c.topic)
+			}
+		})
+	}
+}
+
+func TestOnExcept(t *testing.T) {
+	o := New[string]()
+
+	onAll := &topicEvent{}
+	o.OnExcept([]string{"$", "internal."}, onAll)
+	o.Trigger("$sub").Trigger("internal.gc").Trigger("foo")
+	if onAll.n != 1 || onAll.topic != "foo" {
+		t.Errorf("The ALL event receives %d messages of %s instead of %d messages of %s", onAll.n, onAll.topic, 1, "foo")
+	}
+
+	o.Broadcast()
+	if onAll.n != 2 || onAll.topic != ALL {
+		t.Errorf("The ALL event receives %d messages of %s instead of %d messages of %s", onAll.n, onAll.topic, 2, ALL)
+	}
+
+	o.Off(ALL, onAll).Trigger("foo")
+	if onAll.n != 2 {
+		t.Errorf("The counter is %d instead of being %d", onAll.n, 2)
+	}
+}
+
+type nameEvent struct {
+	name string
+	logs *[]string
+}
+
+func (e *nameEvent) Dispatch(topic string, data ...string) {
+	*e.logs = append(*e.logs, e.name)
+}
+
+func TestAsteriskOrder(t *testing.T) {
+	cases := []struct {
+		name  string
+		first bool
+		logs  string
+	}{
+		{"Asterisk last", false, "[foo all once foo all]"},
+		{"Asterisk first", true, "[all once foo all foo]"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			o := New[string]()
+			if c.first {
+				o.AsteriskFirst()
+			}
+			logs := []string{}
+			o.On("foo", &nameEvent{"foo", &logs})
+			o.On(ALL, &nameEvent{"all", &logs}).Once(ALL, &nameEvent{"once", &logs})
+
+			o.Trigger("foo").Trigger("foo")
+			if s := fmt.Sprint(logs); s != c.logs {
+				t.Errorf("The order is %s instead of being %s", s, c.logs)
+			}
+		})
+	}
+}
+
+type mutateEvent struct{}
+
+func (e *mutateEvent) Dispatch(topic string, data ...string) {
+	data[0] = "mutated"
+}
+
+func TestCopyPayload(t *testing.T) {
+	cases := []struct {
+		name string
+		copy bool
+		s    string
+	}{
+		{"Shared payload", false, "mutated"},
+		{"Copied payload", true, "foo"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			o := New[string]()
+			if c.copy {
+				o.CopyPayload()
+			}
+			n := 0
+			onFoo := &N{&n, ""}
+			o.On("foo", &mutateEvent{}, onFoo).Trigger("foo", "foo")
+			if onFoo.s != c.s {
+				t.Errorf("The message is %s instead of being %s", onFoo.s, c.s)
+			}
+		})
+	}
+}
+
+func TestNewWithAsterisk(t *testing.T) {
+	o := NewWithAsterisk[string]()
+	n := 0
+
+	onAll := &N{&n, ""}
+	o.On(ALL, onAll).On("foo", &N{&n, ""}).Trigger("foo", "test")
+	if n != 2 {
+		t.Errorf("The counter is %d instead of being %d", n, 2)
+	}
+	if onAll.s != "test" {
+		t.Errorf("The last event name triggered is %s instead of being %s", onAll.s, "test")
+	}
+}
+
+func TestNewLazy(t *testing.T) {
+	o := New[string]()
+	if o.histories.m.Load() != nil || o.debouncers.m.Load() != nil || o.parallels.m.Load() != nil ||
+		o.latencies.m.Load() != nil || o.capacities.m.Load() != nil || o.dedup.Load() != nil {
+		t.Error("The feature maps are allocated by New")
+	}
+
+	// the features still work once they are used
+	n := 0
+	o.On("foo", &N{&n, ""}).Trigger("foo", "test")
+	if o.histories.m.Load() != nil || o.latencies.m.Load() != nil {
+		t.Error("The feature maps are allocated by Trigger")
+	}
+	o.WithHistory("foo", 1).EnableTiming().Trigger("foo", "test")
+	if !o.TriggerID("id", "foo", "test") || o.TriggerID("id", "foo", "test") {
+		t.Error("The duplicated id is dispatched")
+	}
+	if n != 3 {
+		t.Errorf("The counter is %d instead of being %d", n, 3)
+	}
+	if p50, _, _ := o.TopicLatency("foo"); p50 <= 0 {
+		t.Errorf("The latency is %v instead of being positive", p50)
+	}
+}
+
+func TestTopicDispatch(t *testing.T) {
+	o := New[string]().AllowAsterisk(true)
+	n := 0
+
+	onFoo := &N{&n, ""}
+	onAll := &N{&n, ""}
+	o.On("foo", onFoo).On(ALL, onAll)
+
+	o.Get("foo").Dispatch("foo")
+	if n != 2 {
+		t.Errorf("The counter is %d instead of being %d", n, 2)
+	}
+	if onAll.s != "foo" {
+		t.Errorf("The last event name triggered is %s instead of being %s", onAll.s, "foo")
+	}
+
+	// the topic without any listener still reaches the ALL events
+	o.Get("bar").Dispatch("bar")
+	if n != 3 {
+		t.Errorf("The counter is %d instead of being %d", n, 3)
+	}
+
+	o.AllowAsterisk(false)
+	o.Get("foo").Dispatch("baz")
+	if n != 4 {
+		t.Errorf("The counter is %d instead of being %d", n, 4)
+	}
+	if onAll.s != "bar" {
+		t.Errorf("The last event name triggered is %s instead of being %s", onAll.s, "bar")
+	}
+}
+
+func TestTopicMethods(t *testing.T) {
+	o := New[string]()
+	n := 0
+	all := 0
+
+	foo := o.Get("foo")
+	if foo.Name() != "foo" {
+		t.Errorf("The topic name is %s instead of being %s", foo.Name(), "foo")
+	}
+	if !foo.IsEmpty() || foo.HasOnce() {
+		t.Error("The topic foo should be empty")
+	}
+
+	o.On("foo", &N{&n, ""}).On(ALL, &N{&all, ""})
+	foo = o.Get("foo")
+	if foo.IsEmpty() || foo.HasOnce() {
+		t.Error("The topic foo should have an event without once event")
+	}
+	o.Once("foo", &N{&n, ""})
+	if !foo.HasOnce() {
+		t.Error("The topic foo should have a once event")
+	}
+
+	foo.DispatchLocal("test")
+	if n != 2 {
+		t.Errorf("The counter is %d instead of being %d", n, 2)
+	}
+	if all != 0 {
+		t.Errorf("The ALL counter is %d instead of being %d", all, 0)
+	}
+	if foo.HasOnce() {
+		t.Error("The called once event should be removed")
+	}
+}
+
+func TestTopicStale(t *testing.T) {
+	o := New[string]()
+	n := 0
+	onFoo := &N{&n, ""}
+
+	// the topic got before the first subscription sees the events subscribed later
+	foo := o.Get("foo")
+	o.On("foo", onFoo)
+	foo.Dispatch("test")
+	if n != 1 || foo.Count() != 1 || foo.IsEmpty() {
+		t.Errorf("The counter is %d and the count is %d instead of being %d and %d", n, foo.Count(), 1, 1)
+	}
+
+	// the topic removed once empty and subscribed again
+	o.Off("foo", onFoo).Once("foo", onFoo)
+	if !foo.HasOnce() {
+		t.Error("The topic foo should have a once event")
+	}
+	foo.DispatchLocal("test")
+	if n != 2 || foo.Count() != 0 {
+		t.Errorf("The counter is %d and the count is %d instead of being %d and %d", n, foo.Count(), 2, 0)
+	}
+}
+
+func TestWithTopicCapacity(t *testing.T) {
+	o := New[string]().WithTopicCapacity("foo", 4)
+	n := 0
+
+	o.On("foo", &N{&n, ""})
+	if c := cap(o.Get("foo").snapshot()); c != 4 {
+		t.Errorf("The capacity is %d instead of being %d", c, 4)
+	}
+
+	// the snapshot taken before is untouched by the events appended in place
+	snapshot := o.Get("foo").snapshot()
+	o.On("foo", &N{&n, ""}, &N{&n, ""})
+	if len(snapshot) != 1 {
+		t.Errorf("The snapshot length is %d instead of being %d", len(snapshot), 1)
+	}
+	if c := cap(o.Get("foo").snapshot()); c != 4 {
+		t.Errorf("The capacity is %d instead of being %d", c, 4)
+	}
+	o.Trigger("foo")
+	if n != 3 {
+		t.Errorf("The counter is %d instead of being %d", n, 3)
+	}
+
+	// the removal keeps the capacity, the hint is applied to the existing topic too
+	o.Off("foo")
+	o.On("foo", &N{&n, ""})
+	o.WithTopicCapacity("foo", 8).On("foo", &N{&n, ""}, &N{&n, ""}, &N{&n, ""}, &N{&n, ""})
+	if c := cap(o.Get("foo").snapshot()); c != 8 {
+		t.Errorf("The capacity is %d instead of being %d", c, 8)
+	}
+}
+
+func TestStats(t *testing.T) {
+	o := New[string]().Throttle("bar", time.Second)
+	n := 0
+
+	o.On("foo", &N{&n, ""}, &N{&n, ""}).On("bar", &N{&n, ""}).On(ALL, &N{&n, ""})
+	o.Trigger("foo").Trigger("bar").Trigger("bar")
+
+	stats := o.Stats()
+	want := BusStats{Topics: o.TopicCount(), Events: 4, Dispatches: o.DispatchCount(), Dropped: o.DroppedCount()}
+	if stats != want {
+		t.Errorf("The stats are %+v instead of being %+v", stats, want)
+	}
+	if stats.Topics != 3 || stats.Dispatches != 5 || stats.Dropped != 1 {
+		t.Errorf("The stats are %+v", stats)
+	}
+}
+
+func TestDispatchCount(t *testing.T) {
+	o := New[string]()
+	n := 0
+
+	o.On("foo", &N{&n, ""}, &N{&n, ""}).Once("bar", &N{&n, ""}).On(ALL, &N{&n, ""})
+	o.Trigger("foo").Trigger("bar").Trigger("bar").Trigger("baz")
+	if c := o.DispatchCount(); c != 7 {
+		t.Errorf("The dispatch count is %d instead of being %d", c, 7)
+	}
+
+	o.ResetStats().Trigger("foo")
+	if c := o.DispatchCount(); c != 3 {
+		t.Errorf("The dispatch count is %d instead of being %d", c, 3)
+	}
+}
+
+func TestRename(t *testing.T) {
+	o := New[string]()
+	n := 0
+
+	if o.Rename("foo", "bar") {
+		t.Error("The topic foo should not exist")
+	}
+
+	o.On("foo", &N{&n, ""}, &N{&n, ""}).Once("foo", &N{&n, ""}).On("bar", &N{&n, ""})
+	if !o.Rename("foo", "bar") {
+		t.Error("The topic foo should exist")
+	}
+	if o.Exists("foo") {
+		t.Error("The topic foo should be removed")
+	}
+	if c := o.EventCount("bar"); c != 4 {
+		t.Errorf("The event count is %d instead of being %d", c, 4)
+	}
+
+	o.Trigger("foo")
+	if n != 0 {
+		t.Errorf("The counter is %d instead of being %d", n, 0)
+	}
+	o.Trigger("bar").Trigger("bar")
+	if n != 7 {
+		t.Errorf("The counter is %d instead of being %d", n, 7)
+	}
+
+	// the subscription follows the renamed event
+	s := o.OnTTL("baz", time.Hour, &N{&n, ""})
+	o.Rename("baz", "qux")
+	s.Unsubscribe()
+	if o.Exists("qux") {
+		t.Error("The topic qux should be removed")
+	}
+}
+
+func TestRenameConcurrent(t *testing.T) {
+	o := New[string]()
+	var counter int64
+	var wg sync.WaitGroup
+
+	o.On("foo", &benchmarkEvent{&counter})
+	for i := 0; i < 100; i++ {
+		wg.Add(3)
+		go func() {
+			defer wg.Done()
+			o.Rename("foo", "bar")
+		}()
+		go func() {
+			defer wg.Done()
+			o.Rename("bar", "foo")
+		}()
+		go func() {
+			defer wg.Done()
+			o.Trigger("foo").Trigger("bar")
+		}()
+	}
+	wg.Wait()
+
+	if c := o.EventCount("foo") + o.EventCount("bar"); c != 1 {
+		t.Errorf("The event count is %d instead of being %d", c, 1)
+	}
+}
+
+type traceEvent struct {
+	traces []string
+}
+
+func (e *traceEvent) Dispatch(topic string, data ...string) {}
+
+func (e *traceEvent) DispatchMeta(meta map[string]string, topic string, data []string) {
+	e.traces = append(e.traces, meta["trace"])
+}
+
+func TestTriggerMeta(t *testing.T) {
+	o := New[string]()
+	n := 0
+
+	e := &traceEvent{}
+	o.On("foo", e, &N{&n, ""}).On(ALL, e)
+	o.TriggerMeta(map[string]string{"trace": "1"}, "foo", "bar").Trigger("foo")
+	if n != 2 {
+		t.Errorf("The counter is %d instead of being %d", n, 2)
+	}
+	if fmt.Sprint(e.traces) != "[1 1  ]" {
+		t.Errorf("The traces are %q instead of being %q", e.traces, []string{"1", "1", "", ""})
+	}
+}
+
+func TestClone(t *testing.T) {
+	o := New[string]()
+	n := 0
+
+	e := &N{&n, ""}
+	o.On("foo", &N{&n, ""}, &N{&n, ""}).Once("bar", e).Once("qux", e).Once("baz", &N{&n, ""})
+	o.OnTTL("foo", time.Hour, &N{&n, ""})
+	o.Trigger("baz")
+	n = 0
+
+	c := o.Clone()
+	if fmt.Sprint(c.CountSnapshot()) != "map[bar:1 foo:2 qux:1]" {
+		t.Errorf("The cloned counts are %v instead of being %s", c.CountSnapshot(), "map[bar:1 foo:2 qux:1]")
+	}
+
+	// the once handler is shared by the topics of the clone, and the buses are independent
+	c.Trigger("bar").Trigger("bar").Trigger("foo")
+	if n != 3 {
+		t.Errorf("The counter is %d instead of being %d", n, 3)
+	}
+	if !o.IsPending("qux", e) || c.IsPending("qux", e) {
+		t.Error("The once event should be called on the clone only")
+	}
+	o.Trigger("foo")
+	if n != 6 {
+		t.Errorf("The counter is %d instead of being %d", n, 6)
+	}
+}
+
+func TestCloneExcept(t *testing.T) {
+	o := New[string]()
+	e := &CaptureEvent[string]{}
+
+	// the cloned OnExcept event keeps skipping the excluded topics
+	o.OnExcept([]string{"$"}, e).Clone().Trigger("$sys", "1").Trigger("foo", "2")
+	if calls := e.Calls(); fmt.Sprint(calls) != "[{foo [2]}]" {
+		t.Errorf("The calls are %v instead of being [{foo [2]}]", calls)
+	}
+}
+
+func TestCloneFallback(t *testing.T) {
+	o := New[string]()
+	fallback := &CaptureEvent[string]{}
+	normal := &CaptureEvent[string]{}
+
+	// the cloned fallback is still superseded by the normal event
+	o.OnFallback("foo", fallback).On("foo", normal).Clone().Trigger("foo", "1")
+	if n := fallback.Len(); n != 0 {
+		t.Errorf("The counter is %d instead of being %d", n, 0)
+	}
+	if n := normal.Len(); n != 1 {
+		t.Errorf("The counter is %d instead of being %d", n, 1)
+	}
+}
+
+func TestCloneSuspended(t *testing.T) {
+	o := New[string]()
+	e := &CaptureEvent[string]{}
+
+	// the cloned event stays suspended, and it is resumed independently of the original
+	c := o.On("foo", e).Suspend("foo", e).Clone()
+	c.Trigger("foo", "1")
+	if n := e.Len(); n != 0 {
+		t.Errorf("The counter is %d instead of being %d", n, 0)
+	}
+	c.Unsuspend("foo", e).Trigger("foo", "2")
+	o.Trigger("foo", "3")
+	if calls := e.Calls(); fmt.Sprint(calls) != "[{foo [2]}]" {
+		t.Errorf("The calls are %v instead of being [{foo [2]}]", calls)
+	}
+}
+
+func TestCloneWrappers(t *testing.T) {
+	o := New[string]()
+	async := &CaptureEvent[string]{}
+	serial := &CaptureEvent[string]{}
+	conflate := &CaptureEvent[string]{}
+	fail := &failEvent{}
+
+	o.OnAsync("foo", 10, async).OnSerial("foo", serial).OnConflate("foo", conflate).OnE("foo", fail)
+	c := o.Clone()
+
+	// the wrappers of the clone have their own goroutines, removing the original doesn't silence them
+	o.Off("foo", async, serial, conflate).OffE("foo", fail)
+	c.Trigger("foo", "1").Flush()
+	if async.Len() != 1 || serial.Len() != 1 || conflate.Len() != 1 || fail.n != 1 {
+		t.Errorf("The counters are %d, %d, %d and %d instead of being %d",
+			async.Len(), serial.Len(), conflate.Len(), fail.n, 1)
+	}
+
+	// the cloned wrappers are removed by the registered events
+	c.Off("foo", async, serial, conflate).OffE("foo", fail)
+	if n := c.EventCount("foo"); n != 0 {
+		t.Errorf("The event count is %d instead of being %d", n, 0)
+	}
+}
+
+func TestCountSnapshot(t *testing.T) {
+	o := New[string]()
+	n := 0
+
+	if c := o.CountSnapshot(); len(c) != 0 {
+		t.Errorf("The snapshot is %v instead of being empty", c)
+	}
+
+	o.On("foo", &N{&n, ""}, &N{&n, ""}).Once("bar", &N{&n, ""}).On(ALL, &N{&n, ""})
+	o.Trigger("bar")
+	counts := o.CountSnapshot()
+	if len(counts) != 2 {
+		t.Errorf("The snapshot is %v instead of having %d topics", counts, 2)
+	}
+	for _, topic := range []string{"foo", "bar", ALL} {
+		if counts[topic] != o.EventCount(topic) {
+			t.Errorf("The count of %s is %d instead of being %d", topic, counts[topic], o.EventCount(topic))
+		}
+	}
+}
+
+type seqEvent struct {
+	mu   sync.Mutex
+	seqs []uint64
+}
+
+func (e *seqEvent) Dispatch(topic string, data ...string) {}
+
+func (e *seqEvent) DispatchSeq(seq uint64, topic string, data []string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.seqs = append(e.seqs, seq)
+}
+
+func TestSeqEvent(t *testing.T) {
+	o := New[string]()
+
+	e := &seqEvent{}
+	o.On("foo", e).On("bar", e).On(ALL, e)
+	o.Trigger("foo").Trigger("bar").Trigger("baz").Broadcast()
+	if fmt.Sprint(e.seqs) != "[1 1 2 2 3 4 4 4]" {
+		t.Errorf("The sequences are %v instead of being %s", e.seqs, "[1 1 2 2 3 4 4 4]")
+	}
+
+	// the sequences increase monotonically for every handler across the interleaved triggers
+	var wg sync.WaitGroup
+	foo, bar := &seqEvent{}, &seqEvent{}
+	o.On("foo", foo).On("bar", bar)
+	for i := 0; i < 2; i++ {
+		wg.Add(1)
+		go func(topic string) {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				o.Trigger(topic)
+			}
+		}([]string{"foo", "bar"}[i])
+	}
+	wg.Wait()
+	for _, e := range []*seqEvent{foo, bar} {
+		if len(e.seqs) != 100 {
+			t.Errorf("The sequence count is %d instead of being %d", len(e.seqs), 100)
+		}
+		for i := 1; i < len(e.seqs); i++ {
+			if e.seqs[i] <= e.seqs[i-1] {
+				t.Errorf("The sequence %d is not greater than %d", e.seqs[i], e.seqs[i-1])
+			}
+		}
+	}
+}
+
+func TestDedupPerDispatch(t *testing.T) {
+	o := New[string]()
+	n := 0
+
+	e := &N{&n, ""}
+	o.On("foo", e, e).On(ALL, e)
+	o.Trigger("foo")
+	if n != 3 {
+		t.Errorf("The counter is %d instead of being %d", n, 3)
+	}
+
+	o.DedupPerDispatch().Trigger("foo").Trigger("foo")
+	if n != 5 {
+		t.Errorf("The counter is %d instead of being %d", n, 5)
+	}
+
+	// the other handlers are not affected
+	o.Once("foo", &N{&n, ""}).Trigger("foo")
+	if n != 7 {
+		t.Errorf("The counter is %d instead of being %d", n, 7)
+	}
+}
+
+func TestTriggerIf(t *testing.T) {
+	o := New[string]()
+	n := 0
+	some := func(count int) bool { return count > 0 }
+
+	o.On(ALL, &N{&n, ""})
+	if o.TriggerIf("foo", some) || n != 0 {
+		t.Errorf("The message should not be dispatched, the counter is %d", n)
+	}
+
+	o.On("foo", &N{&n, ""})
+	if !o.TriggerIf("foo", some) || n != 2 {
+		t.Errorf("The message should be dispatched, the counter is %d instead of being %d", n, 2)
+	}
+	if o.TriggerIf("foo", func(count int) bool { return count > 1 }) || n != 2 {
+		t.Errorf("The message should not be dispatched, the counter is %d", n)
+	}
+
+	// the event subscribed after the count is not called
+	late := 0
+	if !o.TriggerIf("foo", func(count int) bool {
+		o.On("foo", &N{&late, ""})
+		return count == 1
+	}) || n != 4 || late != 0 {
+		t.Errorf("The counters are %d and %d instead of being %d and %d", n, late, 4, 0)
+	}
+
+	// the fallback events are not counted
+	fallback := 0
+	o.OnFallback("bar", &N{&fallback, ""})
+	if o.TriggerIf("bar", some) || fallback != 0 {
+		t.Errorf("The message should not be dispatched, the fallback counter is %d", fallback)
+	}
+	if !o.TriggerIf("bar", func(count int) bool { return count == 0 }) || fallback != 1 {
+		t.Errorf("The fallback counter is %d instead of being %d", fallback, 1)
+	}
+
+	// the event removed while the message is held is not called
+	removed := 0
+	onBaz := &N{&removed, ""}
+	o.On("baz", onBaz).PauseAll()
+	if !o.TriggerIf("baz", some) {
+		t.Error("The message should be dispatched")
+	}
+	o.Off("baz", onBaz).ResumeAll()
+	if removed != 0 {
+		t.Errorf("The removed event is called %d times", removed)
+	}
+}
+
+func TestTriggerMany(t *testing.T) {
+	o := New[string]()
+	n := 0
+	all := 0
+
+	onFoo, onBar, onBaz := &N{&n, ""}, &N{&n, ""}, &N{&n, ""}
+	o.On("foo", onFoo).On("bar", onBar).On("baz", onBaz).On("qux", &N{&n, ""}).On(ALL, &N{&all, ""})
+	o.TriggerMany([]string{"foo", "bar", "baz"}, "test")
+	if n != 3 {
+		t.Errorf("The counter is %d instead of being %d", n, 3)
+	}
+	if onFoo.s != "test" || onBar.s != "test" || onBaz.s != "test" {
+		t.Error("The message should be dispatched to each topic")
+	}
+	if all != 3 {
+		t.Errorf("The ALL counter is %d instead of being %d", all, 3)
+	}
+}
 
-	o.Trigger("foo", "foo").Trigger("bar", "bar")
+func TestTriggerThen(t *testing.T) {
+	o := New[string]()
+	n := 0
+	var handlers []int
+	done := func(c int) {
+		handlers = append(handlers, c)
+	}
 
-	o.Off(ALL, onAll)
+	o.TriggerThen("foo", done, "test1")
+	o.On("foo", &N{&n, ""}, &N{&n, ""}).On(ALL, &N{&n, ""})
+	o.TriggerThen("foo", done, "test2")
+	if s := fmt.Sprint(handlers); s != "[0 3]" {
+		t.Errorf("The handlers are %s instead of being %s", s, "[0 3]")
+	}
 
-	o.Trigger("bar", "bar").Trigger("foo", "bar")
+	// done is called after the held message is dispatched
+	o.PauseAll().TriggerThen("foo", done, "test3")
+	if len(handlers) != 2 {
+		t.Errorf("The handlers are %v before the bus is resumed", handlers)
+	}
+	o.ResumeAll()
+	if s := fmt.Sprint(handlers); s != "[0 3 3]" {
+		t.Errorf("The handlers are %s instead of being %s", s, "[0 3 3]")
+	}
 
-	if onAll.s != "bar" {
-		t.Errorf("The last event name triggered is %s instead of being %s", onAll.s, "bar")
+	o.Throttle("foo", time.Second).TriggerThen("foo", done, "test4").TriggerThen("foo", done, "test5")
+	if s := fmt.Sprint(handlers); s != "[0 3 3 3 0]" {
+		t.Errorf("The handlers are %s instead of being %s", s, "[0 3 3 3 0]")
 	}
+	if n != 9 {
+		t.Errorf("The counter is %d instead of being %d", n, 9)
+	}
+}
 
-	if n != 2 {
-		t.Errorf("The counter is %d instead of being %d", n, 2)
+func TestEnableBubbling(t *testing.T) {
+	log := &orderLog{}
+	o := New[string]().EnableBubbling(".")
+
+	o.On("a", &indexEvent{1, log})
+	o.On("a.b", &indexEvent{2, log})
+	o.On("a.b.c", &indexEvent{3, log})
+	o.On("a.x", &indexEvent{4, log})
+	o.On("b", &indexEvent{5, log})
+	o.On(ALL, &indexEvent{0, log})
+
+	o.Trigger("a.b.c", "c")
+	o.Trigger("a.b", "b")
+	o.Trigger("a.b.d", "d")
+	want := []string{"c:3", "c:2", "c:1", "c:0", "b:2", "b:1", "b:0", "d:2", "d:1", "d:0"}
+	if entries := log.reset(); fmt.Sprint(entries) != fmt.Sprint(want) {
+		t.Errorf("The order is %v instead of being %v", entries, want)
 	}
 
+	o.TriggerDirect("a.b.c", "c")
+	if entries := log.reset(); fmt.Sprint(entries) != "[c:3]" {
+		t.Errorf("The order is %v instead of being [c:3]", entries)
+	}
 }
 
-func TestClean(t *testing.T) {
+func TestTriggerDirect(t *testing.T) {
 	o := New[string]()
-	n := 0
 
-	fn := &N{&n, ""}
-	o.On("foo", fn)
+	onFoo := &CaptureEvent[string]{}
+	onAll := &CaptureEvent[string]{}
+	o.On("foo", onFoo).On(ALL, onAll)
 
-	o.On("bar", fn)
+	o.TriggerDirect("foo", "test1")
+	if n := onFoo.Len(); n != 1 {
+		t.Errorf("The counter is %d instead of being %d", n, 1)
+	}
+	if all := onAll.Len(); all != 0 {
+		t.Errorf("The ALL counter is %d instead of being %d", all, 0)
+	}
 
-	o.Clean()
+	// the other triggers still reach the ALL events
+	o.Trigger("foo", "test2")
+	if calls := onAll.Calls(); fmt.Sprint(calls) != "[{foo [test2]}]" {
+		t.Errorf("The ALL calls are %v instead of being [{foo [test2]}]", calls)
+	}
+}
 
-	o.Trigger("foo").Trigger("bar").Trigger("foo bar")
+func TestTriggerTo(t *testing.T) {
+	o := New[string]()
+	n, m := 0, 0
 
-	if n != 0 {
-		t.Errorf("The counter is %d instead of being %d", n, 0)
+	target := &N{&m, ""}
+	o.On("foo", &N{&n, ""}, target, &N{&n, ""}).On(ALL, &N{&n, ""})
+	if !o.TriggerTo("foo", target, "1") {
+		t.Error("The target should be called")
+	}
+	if m != 1 || n != 0 {
+		t.Errorf("The counters are %d and %d instead of being %d and %d", m, n, 1, 0)
+	}
+	if o.TriggerTo("bar", target) || o.TriggerTo("foo", &N{&n, ""}) {
+		t.Error("The target which is not registered should not be called")
+	}
+
+	// the once target is removed after it is called
+	once := &N{&m, ""}
+	o.Once("foo", once)
+	if !o.TriggerTo("foo", once) || o.TriggerTo("foo", once) {
+		t.Error("The once target should be called only once")
+	}
+	if m != 2 || o.Has("foo", once) {
+		t.Errorf("The counter is %d instead of being %d", m, 2)
 	}
 }
 
-func TestOff(t *testing.T) {
+type totalEvent struct {
+	totals *[]int
+}
+
+func (e *totalEvent) Dispatch(topic string, data ...string) {}
+
+func (e *totalEvent) DispatchN(topic string, data []string, total int) {
+	*e.totals = append(*e.totals, total)
+}
+
+func TestDispatchN(t *testing.T) {
 	o := New[string]()
 	n := 0
+	totals := []int{}
 
-	onFoo1 := &N{&n, ""}
+	o.On("foo", &totalEvent{&totals}, &totalEvent{&totals}).On(ALL, &totalEvent{&totals})
+	o.Trigger("foo")
+	if fmt.Sprint(totals) != "[3 3 3]" {
+		t.Errorf("The totals are %v instead of being %v", totals, []int{3, 3, 3})
+	}
 
-	onFoo2 := &N{&n, ""}
+	totals = totals[:0]
+	o.Once("foo", &N{&n, ""}).Trigger("foo").Trigger("bar")
+	if fmt.Sprint(totals) != "[4 4 4 1]" {
+		t.Errorf("The totals are %v instead of being %v", totals, []int{4, 4, 4, 1})
+	}
+}
 
-	o.On("foo", onFoo1).On("foo", onFoo2)
-	o.Trigger("foo", "test1")
-	if onFoo1.s != "test1" {
-		t.Fail()
+func TestTriggerCollect(t *testing.T) {
+	o := New[string]()
+	n := 0
+
+	e1, e2 := &N{&n, ""}, &N{&n, ""}
+	o.On("foo", &N{&n, ""}).Once("foo", e1).On(ALL, &N{&n, ""}).Once(ALL, e2)
+	consumed := o.TriggerCollect("foo")
+	if len(consumed) != 2 || consumed[0] != e1 || consumed[1] != e2 {
+		t.Errorf("The consumed events are %v instead of being %v", consumed, []Event[string]{e1, e2})
 	}
-	if onFoo2.s != "test1" {
-		t.Fail()
+	if n != 4 {
+		t.Errorf("The counter is %d instead of being %d", n, 4)
 	}
 
-	o.Off("foo", onFoo1).Off("foo", onFoo2).On("foo", onFoo1)
-	o.Trigger("foo", "test2")
-	if onFoo1.s != "test2" {
-		t.Fail()
+	if consumed := o.TriggerCollect("foo"); len(consumed) != 0 {
+		t.Errorf("The consumed events are %v instead of being empty", consumed)
 	}
-	if onFoo2.s == "test2" {
-		t.Fail()
+}
+
+func TestBroadcastCollect(t *testing.T) {
+	o := New[string]()
+	n := 0
+
+	e1, e2, e3 := &N{&n, ""}, &N{&n, ""}, &N{&n, ""}
+	o.On("foo", &N{&n, ""}).Once("foo", e1).On("foo", &N{&n, ""})
+	o.Once("bar", e2).Once("baz", e2).On(ALL, &N{&n, ""}).Once(ALL, e3)
+
+	consumed := o.BroadcastCollect("test")
+	if len(consumed["foo"]) != 1 || consumed["foo"][0] != e1 {
+		t.Errorf("The consumed events of foo are %v instead of being %v", consumed["foo"], []Event[string]{e1})
+	}
+	// the once event reachable by two topics is called by one of them only
+	if len(consumed["bar"])+len(consumed["baz"]) != 1 {
+		t.Errorf("The consumed events are %v", consumed)
+	}
+	if len(consumed[ALL]) != 1 || consumed[ALL][0] != e3 {
+		t.Errorf("The consumed events of ALL are %v instead of being %v", consumed[ALL], []Event[string]{e3})
+	}
+	if n != 6 {
+		t.Errorf("The counter is %d instead of being %d", n, 6)
 	}
 
-	o.On("foo", onFoo2).Off("foo", onFoo1)
-	o.Trigger("foo", "test3")
-	if onFoo1.s == "test3" {
-		t.Fail()
+	// the once events are removed even though the topic has other events
+	if c := o.EventCount("foo"); c != 2 {
+		t.Errorf("The event count is %d instead of being %d", c, 2)
 	}
-	if onFoo2.s != "test3" {
-		t.Fail()
+	if o.Exists("bar") || o.Exists("baz") {
+		t.Error("The topics bar and baz should be removed")
+	}
+	if consumed := o.BroadcastCollect("test"); len(consumed) != 0 {
+		t.Errorf("The consumed events are %v instead of being empty", consumed)
 	}
+}
 
-	if n != 4 {
+type singleEvent struct {
+	data []string
+}
+
+func (e *singleEvent) Dispatch(topic string, data ...string) {}
+
+func (e *singleEvent) DispatchOne(topic string, data string) {
+	e.data = append(e.data, data)
+}
+
+func TestSingleEvent(t *testing.T) {
+	o := New[string]()
+	n := 0
+
+	e := &singleEvent{}
+	o.On("foo", e, &N{&n, ""})
+	o.Trigger("foo", "a", "b").Trigger("foo", "c").Trigger("foo")
+	if n != 3 {
 		t.Errorf("The counter is %d instead of being %d", n, 3)
 	}
+	if fmt.Sprint(e.data) != "[a b c]" {
+		t.Errorf("The data is %v instead of being %v", e.data, []string{"a", "b", "c"})
+	}
+}
 
+func TestSingleEventWrapped(t *testing.T) {
+	cases := []struct {
+		name string
+		on   func(o *Bus[string], e *singleEvent)
+	}{
+		{"OnSerial", func(o *Bus[string], e *singleEvent) { o.OnSerial("foo", e) }},
+		{"OnAsync", func(o *Bus[string], e *singleEvent) { o.OnAsync("foo", 10, e) }},
+		{"OnConflate", func(o *Bus[string], e *singleEvent) { o.OnConflate("foo", e) }},
+		{"OnReplay", func(o *Bus[string], e *singleEvent) { o.WithHistory("foo", 1).OnReplay("foo", e) }},
+		{"OnWeak", func(o *Bus[string], e *singleEvent) { OnWeak(o, "foo", e) }},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			o := New[string]()
+
+			// the wrapped event receives the data by DispatchOne like the event registered by On
+			e := &singleEvent{}
+			c.on(o, e)
+			o.Trigger("foo", "a", "b").Flush()
+			if fmt.Sprint(e.data) != "[a b]" {
+				t.Errorf("The data is %v instead of being %v", e.data, []string{"a", "b"})
+			}
+			runtime.KeepAlive(e)
+		})
+	}
 }
 
-func TestRace(t *testing.T) {
+func TestTriggerReport(t *testing.T) {
 	o := New[string]()
 	n := 0
 
-	asyncTask := func(wg *sync.WaitGroup) {
-		o.Trigger("foo")
-		wg.Done()
+	o.On("foo", &N{&n, ""}, &N{&n, ""}).Once("foo", &N{&n, ""}).On(ALL, &N{&n, ""}).Once(ALL, &N{&n, ""})
+	r := o.TriggerReport("foo")
+	if r != (DispatchReport{TopicHandlers: 3, AsteriskHandlers: 2, OnceRemoved: 2}) {
+		t.Errorf("The report is %+v instead of being %+v", r, DispatchReport{3, 2, 2})
 	}
-	var wg sync.WaitGroup
 
-	wg.Add(5)
+	r = o.TriggerReport(ALL)
+	if r != (DispatchReport{TopicHandlers: 1}) {
+		t.Errorf("The report is %+v instead of being %+v", r, DispatchReport{1, 0, 0})
+	}
 
-	fn := &N{&n, ""}
-	o.On("foo", fn)
+	r = o.AllowAsterisk(false).TriggerReport("foo")
+	if r != (DispatchReport{TopicHandlers: 2}) {
+		t.Errorf("The report is %+v instead of being %+v", r, DispatchReport{2, 0, 0})
+	}
+}
 
-	go asyncTask(&wg)
-	go asyncTask(&wg)
-	go asyncTask(&wg)
-	go asyncTask(&wg)
-	go asyncTask(&wg)
+func TestTriggerSlice(t *testing.T) {
+	o := New[string]()
 
-	wg.Wait()
+	data := []string{"a", "b"}
+	e := &mutateEvent{}
+	o.On("foo", e).TriggerSlice("foo", data)
+	if data[0] != "mutated" {
+		t.Errorf("The data is %v instead of being shared with the handler", data)
+	}
 
-	if n != 5 {
-		t.Errorf("The counter is %d instead of being %d", n, 5)
+	data = []string{"a", "b"}
+	o.CopyPayload().TriggerSlice("foo", data)
+	if data[0] != "a" {
+		t.Errorf("The data is %v instead of being copied", data)
 	}
+}
 
+func TestTriggerValue(t *testing.T) {
+	o := New[string]()
+
+	e := &CaptureEvent[string]{}
+	o.On("foo", e).On(ALL, e).TriggerValue("foo", "a").TriggerValue("foo", "b")
+	if calls := e.Calls(); fmt.Sprint(calls) != "[{foo [a]} {foo [a]} {foo [b]} {foo [b]}]" {
+		t.Errorf("The calls are %v instead of being [{foo [a]} {foo [a]} {foo [b]} {foo [b]}]", calls)
+	}
 }
 
-func TestOne(t *testing.T) {
+func TestOnHandlerLeak(t *testing.T) {
 	o := New[string]()
 	n := 0
+	leaks := []string{}
 
-	onFoo := &N{&n, ""}
+	o.OnHandlerLeak(3, func(topic string, count int) {
+		leaks = append(leaks, fmt.Sprintf("%s:%d", topic, count))
+	})
+	for i := 0; i < 5; i++ {
+		o.On("foo", &N{&n, ""})
+	}
+	o.On("bar", &N{&n, ""}, &N{&n, ""}, &N{&n, ""}, &N{&n, ""})
+	if fmt.Sprint(leaks) != "[foo:4 bar:4]" {
+		t.Errorf("The leaks are %v instead of being %s", leaks, "[foo:4 bar:4]")
+	}
 
-	o.Once("foo", onFoo)
+	// it is called again after the count crosses the threshold again
+	o.Off("foo").On("foo", &N{&n, ""}, &N{&n, ""}, &N{&n, ""}).On("foo", &N{&n, ""})
+	if fmt.Sprint(leaks) != "[foo:4 bar:4 foo:4]" {
+		t.Errorf("The leaks are %v instead of being %s", leaks, "[foo:4 bar:4 foo:4]")
+	}
+}
 
-	o.Trigger("foo").Trigger("foo").Trigger("foo")
+func TestOnNoListener(t *testing.T) {
+	o := New[string]()
+	n := 0
+	topics := []string{}
 
-	if n != 1 {
-		t.Errorf("The counter is %d instead of being %d", n, 1)
+	o.OnNoListener(func(topic string, data []string) {
+		topics = append(topics, topic)
+	})
+
+	o.Trigger("foo", "foo")
+	if len(topics) != 1 || topics[0] != "foo" {
+		t.Errorf("The topics without listener are %v instead of being %v", topics, []string{"foo"})
+	}
+
+	o.On("bar", &N{&n, ""}).Trigger("bar", "bar")
+	if len(topics) != 1 {
+		t.Errorf("The topics without listener are %v instead of being %v", topics, []string{"foo"})
 	}
 
+	// the ALL events are listeners of every topic when asterisk is allowed
+	o.On(ALL, &N{&n, ""}).Trigger("baz", "baz")
+	if len(topics) != 1 {
+		t.Errorf("The topics without listener are %v instead of being %v", topics, []string{"foo"})
+	}
+
+	o.AllowAsterisk(false).Trigger("baz", "baz")
+	if len(topics) != 2 || topics[1] != "baz" {
+		t.Errorf("The topics without listener are %v instead of being %v", topics, []string{"foo", "baz"})
+	}
 }
 
-func TestArguments(t *testing.T) {
-	o := New[input]()
+type slowEvent struct {
+	release chan struct{}
+}
+
+func (e *slowEvent) Dispatch(topic string, data ...string) {
+	<-e.release
+}
+
+func TestHandlerTimeout(t *testing.T) {
+	o := New[string]()
 	n := 0
-	fn := &FN{&n}
-	o.On("foo", fn)
+	slows := []Event[string]{}
 
-	o.Trigger("foo", input{"bar", true})
+	slow := &slowEvent{make(chan struct{})}
+	defer close(slow.release)
+
+	o.WithHandlerTimeout(10 * time.Millisecond).OnSlowHandler(func(topic string, e Event[string], d time.Duration) {
+		slows = append(slows, e)
+	})
+	o.On("foo", slow, &N{&n, ""})
+
+	done := make(chan struct{})
+	go func() {
+		o.Trigger("foo", "foo")
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("The trigger is blocked by the slow handler")
+	}
 
 	if n != 1 {
 		t.Errorf("The counter is %d instead of being %d", n, 1)
 	}
-}
-
-func TestTrigger(t *testing.T) {
-	o := New[interface{}]()
-	// the trigger without any listener should not throw errors
-	o.Trigger("foo")
+	if len(slows) != 1 || slows[0] != slow {
+		t.Errorf("The slow handlers are %v instead of being %v", slows, []Event[string]{slow})
+	}
 }
 
 /**
@@ -223,6 +2152,41 @@ func BenchmarkOnTrigger(b *testing.B) {
 	}
 }
 
+// 基准测试：分发计数器的开销，对比 Trigger 的总耗时和其中每次分发对计数器的一次原子累加，包括并发触发时的竞争
+func BenchmarkDispatchCount(b *testing.B) {
+	o := New[string]()
+	var counter int64
+	for _, e := range eventsList {
+		o.On(e, &benchmarkEvent{&counter})
+	}
+
+	b.Run("trigger", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			o.Trigger(eventsList[i%len(eventsList)], "foo")
+		}
+	})
+	b.Run("counter", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			o.dispatches.Add(1)
+		}
+	})
+	b.Run("trigger-parallel", func(b *testing.B) {
+		b.RunParallel(func(pb *testing.PB) {
+			for i := 0; pb.Next(); i++ {
+				o.Trigger(eventsList[i%len(eventsList)], "foo")
+			}
+		})
+	})
+	b.Run("counter-parallel", func(b *testing.B) {
+		b.RunParallel(func(pb *testing.PB) {
+			for pb.Next() {
+				o.dispatches.Add(1)
+			}
+		})
+	})
+}
+
 // 模拟事件处理器
 type benchmarkEvent struct {
 	counter *int64
@@ -257,6 +2221,61 @@ func BenchmarkConcurrentSubscribeAndTrigger(b *testing.B) {
 	b.ReportMetric(float64(atomic.LoadInt64(&counter)), "dispatches")
 }
 
+// 基准测试：慢处理函数运行期间订阅和取消订阅同一主题
+func BenchmarkSubscribeDuringSlowDispatch(b *testing.B) {
+	bus := New[string]()
+	var counter int64
+
+	// 处理函数在锁外调用，慢处理函数不会阻塞同一主题的订阅
+	bus.On("topic", &sleepEvent{time.Millisecond, new(int64)})
+	stop := make(chan struct{})
+	go func() {
+		for {
+			select {
+			case <-stop:
+				return
+			default:
+				bus.Trigger("topic", "test message")
+			}
+		}
+	}()
+
+	b.ResetTimer()
+
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			event := &benchmarkEvent{&counter}
+			bus.On("topic", event)
+			bus.Off("topic", event)
+		}
+	})
+
+	b.StopTimer()
+	close(stop)
+}
+
+// 基准测试：同一主题订阅大量事件，不设置容量
+func BenchmarkSubscribeManyToOneTopic(b *testing.B) {
+	var counter int64
+	for i := 0; i < b.N; i++ {
+		bus := New[string]()
+		for j := 0; j < 1000; j++ {
+			bus.On("topic", &benchmarkEvent{&counter})
+		}
+	}
+}
+
+// 基准测试：同一主题订阅大量事件，预先设置容量
+func BenchmarkSubscribeManyToOneTopicWithCapacity(b *testing.B) {
+	var counter int64
+	for i := 0; i < b.N; i++ {
+		bus := New[string]().WithTopicCapacity("topic", 1000)
+		for j := 0; j < 1000; j++ {
+			bus.On("topic", &benchmarkEvent{&counter})
+		}
+	}
+}
+
 // 基准测试：频繁订阅和取消订阅
 func BenchmarkSubscribeUnsubscribe(b *testing.B) {
 	bus := New[string]()
@@ -272,6 +2291,24 @@ func BenchmarkSubscribeUnsubscribe(b *testing.B) {
 	}
 }
 
+// 基准测试：频繁订阅和取消订阅时的协程数量
+func BenchmarkSubscribeUnsubscribeStop(b *testing.B) {
+	bus := New[string]()
+	var stops int64
+	base := runtime.NumGoroutine()
+	peak := base
+
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		event := &stopEvent{&stops}
+		bus.On("topic", event)
+		bus.Off("topic", event)
+		peak = max(peak, runtime.NumGoroutine())
+	}
+	b.ReportMetric(float64(peak-base), "goroutines")
+}
+
 // 基准测试：大量事件的内存使用
 func BenchmarkMemoryUsage(b *testing.B) {
 	bus := New[string]()
@@ -294,6 +2331,60 @@ func BenchmarkMemoryUsage(b *testing.B) {
 	b.ReportMetric(float64(m.Alloc), "bytes_allocated")
 }
 
+// 基准测试：直接触发切片
+func BenchmarkTriggerSlice(b *testing.B) {
+	bus := New[string]()
+	var counter int64
+	bus.On("topic", &benchmarkEvent{&counter})
+	data := []string{"1", "2", "3"}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		bus.TriggerSlice("topic", data)
+	}
+}
+
+// 基准测试：展开切片触发
+func BenchmarkTriggerSpread(b *testing.B) {
+	bus := New[string]()
+	var counter int64
+	bus.On("topic", &benchmarkEvent{&counter})
+	data := []string{"1", "2", "3"}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		bus.Trigger("topic", data...)
+	}
+}
+
+// 基准测试：单个参数触发
+func BenchmarkTriggerOne(b *testing.B) {
+	bus := New[string]()
+	var counter int64
+	bus.On("topic", &benchmarkEvent{&counter})
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		bus.Trigger("topic", "1")
+	}
+}
+
+// 基准测试：单值触发
+func BenchmarkTriggerValue(b *testing.B) {
+	bus := New[string]()
+	var counter int64
+	bus.On("topic", &benchmarkEvent{&counter})
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		bus.TriggerValue("topic", "1")
+	}
+}
+
 // 基准测试：并发订阅、触发和取消订阅
 func BenchmarkConcurrentOperations(b *testing.B) {
 	bus := New[string]()
@@ -0,0 +1,74 @@
+package eventbus
+
+import (
+	"context"
+	"slices"
+	"sync"
+)
+
+// Capture struct, a message received by CaptureEvent
+type Capture[T any] struct {
+	Topic string
+	Data  []T
+}
+
+// CaptureEvent struct, an event which records the received messages for the tests.
+// The zero value is ready to use and it is safe to be called concurrently
+type CaptureEvent[T any] struct {
+	mu     sync.Mutex
+	calls  []Capture[T]
+	notify chan struct{}
+}
+
+func (c *CaptureEvent[T]) Dispatch(topic string, data ...T) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.calls = append(c.calls, Capture[T]{topic, slices.Clone(data)})
+	if c.notify != nil {
+		close(c.notify)
+		c.notify = nil
+	}
+}
+
+// Calls - return the received messages in the arrival order
+func (c *CaptureEvent[T]) Calls() []Capture[T] {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return slices.Clone(c.calls)
+}
+
+// Len - return the number of the received messages
+func (c *CaptureEvent[T]) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.calls)
+}
+
+// Reset - forget the received messages
+func (c *CaptureEvent[T]) Reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.calls = nil
+}
+
+// WaitN - wait until at least n messages are received, the error of ctx is returned if it is done first
+func (c *CaptureEvent[T]) WaitN(ctx context.Context, n int) error {
+	for {
+		c.mu.Lock()
+		if len(c.calls) >= n {
+			c.mu.Unlock()
+			return nil
+		}
+		if c.notify == nil {
+			c.notify = make(chan struct{})
+		}
+		notify := c.notify
+		c.mu.Unlock()
+
+		select {
+		case <-notify:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
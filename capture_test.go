@@ -0,0 +1,41 @@
+package eventbus
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestCaptureEvent(t *testing.T) {
+	o := New[string]()
+	e := &CaptureEvent[string]{}
+	o.On("foo", e).On("bar", e)
+
+	o.TriggerAsync("foo", "1", "2")
+	o.TriggerAsync("bar", "3")
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := e.WaitN(ctx, 2); err != nil {
+		t.Fatalf("The error is %v instead of being nil", err)
+	}
+	if c := e.Len(); c != 2 {
+		t.Errorf("The counter is %d instead of being %d", c, 2)
+	}
+
+	// the data is copied, so that the captures are not changed by the caller
+	data := []string{"4"}
+	e.Reset()
+	o.TriggerSlice("foo", data)
+	data[0] = "5"
+	if calls := e.Calls(); fmt.Sprint(calls) != "[{foo [4]}]" {
+		t.Errorf("The calls are %v instead of being [{foo [4]}]", calls)
+	}
+
+	// the done context stops waiting
+	ctx, cancel = context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := e.WaitN(ctx, 2); err != context.DeadlineExceeded {
+		t.Errorf("The error is %v instead of being %v", err, context.DeadlineExceeded)
+	}
+}
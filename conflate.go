@@ -0,0 +1,8 @@
+package eventbus
+
+// OnConflate - register topic event which is called on a dedicated goroutine with the latest message only.
+// Dispatch of the bus doesn't wait for it, and the message which is not called yet is dropped when a new one
+// arrives, so a slow event always gets the most recent value. Use Flush to wait for the last message
+func (b *Bus[T]) OnConflate(topic string, e Event[T]) *Bus[T] {
+	return b.onMailbox(topic, e, 1, true)
+}
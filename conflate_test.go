@@ -0,0 +1,56 @@
+package eventbus
+
+import (
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+type slowLogEvent struct {
+	mu   sync.Mutex
+	logs []string
+}
+
+func (e *slowLogEvent) Dispatch(topic string, data ...string) {
+	time.Sleep(time.Millisecond)
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.logs = append(e.logs, data...)
+}
+
+func TestOnConflate(t *testing.T) {
+	o := New[string]()
+	n := 0
+
+	e := &slowLogEvent{}
+	o.OnConflate("foo", e).On("foo", &N{&n, ""})
+	for i := 1; i <= 100; i++ {
+		o.Trigger("foo", strconv.Itoa(i))
+	}
+	o.Flush()
+
+	// the other events are called for every message
+	if n != 100 {
+		t.Errorf("The counter is %d instead of being %d", n, 100)
+	}
+	e.mu.Lock()
+	logs := e.logs
+	e.mu.Unlock()
+	if len(logs) == 0 || len(logs) >= 100 {
+		t.Fatalf("The conflating event is called %d times", len(logs))
+	}
+	if last := logs[len(logs)-1]; last != "100" {
+		t.Errorf("The last message is %s instead of being %s", last, "100")
+	}
+	if d := o.DroppedCount(); int(d)+len(logs) != 100 {
+		t.Errorf("The dropped count is %d instead of being %d", d, 100-len(logs))
+	}
+
+	// the event is not called after it is removed
+	o.Off("foo", e).Trigger("foo", "101")
+	o.Flush()
+	if c := len(e.logs); c != len(logs) {
+		t.Errorf("The conflating event is called %d times instead of %d", c, len(logs))
+	}
+}
@@ -0,0 +1,130 @@
+package eventbus
+
+import (
+	"sync"
+	"time"
+)
+
+// debouncer struct, it coalesces the messages of a topic emitted within a window
+type debouncer[T any] struct {
+	mu       sync.Mutex
+	bus      *Bus[T]
+	window   time.Duration
+	leading  bool
+	timer    *time.Timer
+	last     *message[T]
+	count    int
+	deadline time.Time
+	done     func()
+}
+
+// hold returns whether m is held back and the message dropped by it, the passed messages are marked as coalesced
+func (d *debouncer[T]) hold(m *message[T]) (bool, *message[T]) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.leading {
+		now := time.Now()
+		if now.Before(d.deadline) {
+			return true, m
+		}
+		d.deadline = now.Add(d.window)
+		m.coalesced = 1
+		return false, nil
+	}
+
+	dropped := d.last
+	d.last = m
+	d.count++
+	if d.timer == nil {
+		d.done = d.bus.pending.add()
+		d.timer = time.AfterFunc(d.window, d.release)
+	} else {
+		d.timer.Reset(d.window)
+	}
+	return true, dropped
+}
+
+// release dispatches the last message held in the quiet window
+func (d *debouncer[T]) release() {
+	d.mu.Lock()
+	m, done := d.take()
+	d.mu.Unlock()
+	d.dispatch(m, done)
+}
+
+// flush dispatches the held message immediately
+func (d *debouncer[T]) flush() {
+	d.mu.Lock()
+	if d.timer != nil {
+		d.timer.Stop()
+	}
+	m, done := d.take()
+	d.mu.Unlock()
+	d.dispatch(m, done)
+}
+
+// take returns the held message and resets the window, it must be called with the lock held
+func (d *debouncer[T]) take() (*message[T], func()) {
+	m, done := d.last, d.done
+	if m == nil {
+		// the message has been taken by flush or by the previous timer
+		return nil, nil
+	}
+	m.coalesced = d.count
+	m.queued = true
+	d.last = nil
+	d.count = 0
+	d.timer = nil
+	d.done = nil
+	return m, done
+}
+
+func (d *debouncer[T]) dispatch(m *message[T], done func()) {
+	if m == nil {
+		return
+	}
+	defer done()
+	d.bus.dispatch(d.bus.Get(m.topic), m)
+}
+
+// Debounce - dispatch only the last message of the topic after it is quiet for the window,
+// the message is dispatched on another goroutine. A window <= 0 disables it
+func (b *Bus[T]) Debounce(topic string, window time.Duration) *Bus[T] {
+	return b.setDebouncer(topic, window, false)
+}
+
+// Throttle - dispatch the first message of the topic and drop the others emitted within the window,
+// a window <= 0 disables it
+func (b *Bus[T]) Throttle(topic string, window time.Duration) *Bus[T] {
+	return b.setDebouncer(topic, window, true)
+}
+
+func (b *Bus[T]) setDebouncer(topic string, window time.Duration, leading bool) *Bus[T] {
+	if window <= 0 {
+		b.debouncers.remove(topic)
+		return b
+	}
+	b.debouncers.store().Set(topic, &debouncer[T]{bus: b, window: window, leading: leading})
+	return b
+}
+
+// debounced returns whether m is held back by the debouncer of its topic
+func (b *Bus[T]) debounced(m *message[T]) bool {
+	if m.coalesced > 0 {
+		return false
+	}
+	d, ok := b.debouncers.get(m.topic)
+	if !ok {
+		return false
+	}
+	held, dropped := d.hold(m)
+	if dropped != nil {
+		reason := DropDebounce
+		if d.leading {
+			reason = DropThrottle
+		}
+		b.dropMessage(dropped, reason)
+	}
+	return held
+}
@@ -0,0 +1,96 @@
+package eventbus
+
+import (
+	"fmt"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestDebounce(t *testing.T) {
+	o := New[string]().Debounce("foo", 20*time.Millisecond)
+
+	onFoo := &logEvent{}
+	o.On("foo", onFoo)
+	for i := 1; i <= 5; i++ {
+		o.Trigger("foo", strconv.Itoa(i))
+	}
+	if logs := onFoo.Logs(); len(logs) != 0 {
+		t.Errorf("The messages are %v before the window expires", logs)
+	}
+
+	time.Sleep(100 * time.Millisecond)
+	if logs := fmt.Sprint(onFoo.Logs()); logs != "[5]" {
+		t.Errorf("The messages are %s instead of being %s", logs, "[5]")
+	}
+
+	// the other topics are not debounced
+	onBar := &logEvent{}
+	o.On("bar", onBar).Trigger("bar", "1").Trigger("bar", "2")
+	if logs := fmt.Sprint(onBar.Logs()); logs != "[1 2]" {
+		t.Errorf("The messages are %s instead of being %s", logs, "[1 2]")
+	}
+}
+
+func TestThrottle(t *testing.T) {
+	o := New[string]().Throttle("foo", 50*time.Millisecond)
+
+	onFoo := &logEvent{}
+	o.On("foo", onFoo)
+	o.Trigger("foo", "1").Trigger("foo", "2").Trigger("foo", "3")
+	if logs := fmt.Sprint(onFoo.Logs()); logs != "[1]" {
+		t.Errorf("The messages are %s instead of being %s", logs, "[1]")
+	}
+
+	time.Sleep(100 * time.Millisecond)
+	o.Trigger("foo", "4").Trigger("foo", "5")
+	if logs := fmt.Sprint(onFoo.Logs()); logs != "[1 4]" {
+		t.Errorf("The messages are %s instead of being %s", logs, "[1 4]")
+	}
+
+	o.Throttle("foo", 0).Trigger("foo", "6")
+	if logs := fmt.Sprint(onFoo.Logs()); logs != "[1 4 6]" {
+		t.Errorf("The messages are %s instead of being %s", logs, "[1 4 6]")
+	}
+}
+
+type coalescedEvent struct {
+	mu     sync.Mutex
+	counts []int
+}
+
+func (e *coalescedEvent) Dispatch(topic string, data ...string) {}
+
+func (e *coalescedEvent) DispatchCoalesced(topic string, data []string, coalesced int) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.counts = append(e.counts, coalesced)
+}
+
+func (e *coalescedEvent) Counts() string {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return fmt.Sprint(e.counts)
+}
+
+func TestDispatchCoalesced(t *testing.T) {
+	o := New[string]().Debounce("foo", 20*time.Millisecond)
+
+	e := &coalescedEvent{}
+	o.On("foo", e).On("bar", e)
+	for i := 1; i <= 5; i++ {
+		o.Trigger("foo", strconv.Itoa(i))
+	}
+	o.Flush()
+	for i := 1; i <= 3; i++ {
+		o.Trigger("foo", strconv.Itoa(i))
+	}
+	time.Sleep(100 * time.Millisecond)
+
+	// the other topics are not debounced
+	o.Trigger("bar", "1")
+	if counts := e.Counts(); counts != "[5 3 1]" {
+		t.Errorf("The counts are %s instead of being %s", counts, "[5 3 1]")
+	}
+}
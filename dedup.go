@@ -0,0 +1,69 @@
+package eventbus
+
+import (
+	"container/list"
+	"sync"
+)
+
+// DefaultDedupSize - the default number of the message ids remembered by TriggerID
+const DefaultDedupSize = 1024
+
+// dedupCache struct, a LRU set of the recently seen message ids
+type dedupCache struct {
+	mu    sync.Mutex
+	size  int
+	ids   map[string]*list.Element
+	order *list.List
+}
+
+func newDedupCache(size int) *dedupCache {
+	return &dedupCache{
+		size:  size,
+		ids:   make(map[string]*list.Element, size),
+		order: list.New(),
+	}
+}
+
+// seen returns whether id has been seen, the id is remembered and the least recently seen one is evicted
+func (c *dedupCache) seen(id string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if e, ok := c.ids[id]; ok {
+		c.order.MoveToFront(e)
+		return true
+	}
+	c.ids[id] = c.order.PushFront(id)
+	if c.order.Len() > c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.ids, oldest.Value.(string))
+	}
+	return false
+}
+
+// WithDedupCache - set the number of the message ids remembered by TriggerID, default is DefaultDedupSize
+func (b *Bus[T]) WithDedupCache(n int) *Bus[T] {
+	if n <= 0 {
+		n = DefaultDedupSize
+	}
+	b.dedup.Store(newDedupCache(n))
+	return b
+}
+
+// dedupIDs returns the cache of TriggerID, the default one is created on the first use
+func (b *Bus[T]) dedupIDs() *dedupCache {
+	if c := b.dedup.Load(); c != nil {
+		return c
+	}
+	b.dedup.CompareAndSwap(nil, newDedupCache(DefaultDedupSize))
+	return b.dedup.Load()
+}
+
+// TriggerID - dispatch event unless the message id has been seen recently, it returns whether it is dispatched
+func (b *Bus[T]) TriggerID(id string, topic string, msg ...T) bool {
+	if b.dedupIDs().seen(id) {
+		return false
+	}
+	b.Emit(topic, msg...)
+	return true
+}
@@ -0,0 +1,34 @@
+package eventbus
+
+import (
+	"testing"
+)
+
+func TestTriggerID(t *testing.T) {
+	o := New[string]().WithDedupCache(2)
+	n := 0
+	o.On("foo", &N{&n, ""})
+
+	if !o.TriggerID("1", "foo", "a") {
+		t.Error("The first message should be dispatched")
+	}
+	if o.TriggerID("1", "foo", "a") {
+		t.Error("The duplicated message should be skipped")
+	}
+	if n != 1 {
+		t.Errorf("The counter is %d instead of being %d", n, 1)
+	}
+
+	// the least recently seen id is evicted
+	o.TriggerID("2", "foo", "b")
+	o.TriggerID("3", "foo", "c")
+	if !o.TriggerID("1", "foo", "a") {
+		t.Error("The evicted id should be dispatched again")
+	}
+	if o.TriggerID("3", "foo", "c") {
+		t.Error("The recent id should be skipped")
+	}
+	if n != 4 {
+		t.Errorf("The counter is %d instead of being %d", n, 4)
+	}
+}
@@ -0,0 +1,77 @@
+package eventbus
+
+// DropReason type, why a message is dropped without being dispatched
+type DropReason int
+
+const (
+	// DropClosed - the message is triggered after the bus is closed
+	DropClosed DropReason = iota
+	// DropPaused - the message is triggered while the bus is paused and it isn't buffered
+	DropPaused
+	// DropDebounce - the message is replaced by a later message of the debounced topic
+	DropDebounce
+	// DropThrottle - the message is triggered within the window of the throttled topic
+	DropThrottle
+	// DropConflate - the message is replaced by a later message before the conflating event is called
+	DropConflate
+	// DropStopped - the message is queued for the event of OnAsync, OnSerial or OnConflate which is removed before it is called
+	DropStopped
+)
+
+func (r DropReason) String() string {
+	switch r {
+	case DropClosed:
+		return "closed"
+	case DropPaused:
+		return "paused"
+	case DropDebounce:
+		return "debounce"
+	case DropThrottle:
+		return "throttle"
+	case DropConflate:
+		return "conflate"
+	case DropStopped:
+		return "stopped"
+	}
+	return "unknown"
+}
+
+// OnDrop - set the callback which is called when a message is dropped without being dispatched,
+// the topic of a dropped broadcast is ALL
+func (b *Bus[T]) OnDrop(fn func(topic string, reason DropReason, data []T)) *Bus[T] {
+	b.dropHandler = fn
+	return b
+}
+
+// DroppedCount - return the number of the dropped messages since the bus is created or the stats are reset
+func (b *Bus[T]) DroppedCount() uint64 {
+	return b.dropped.Load()
+}
+
+// drop counts the dropped message and passes it to the drop handler
+func (b *Bus[T]) drop(topic string, reason DropReason, data []T) {
+	b.dropped.Add(1)
+	if b.dropHandler != nil {
+		b.dropHandler(topic, reason, data)
+	}
+}
+
+// admit returns whether a call returning the result of the handlers may run, data is dropped after Shutdown
+// or while the bus is paused since the call can't wait to be buffered
+func (b *Bus[T]) admit(topic string, data []T) bool {
+	if b.closed.Load() {
+		b.drop(topic, DropClosed, data)
+		return false
+	}
+	if b.pause.on.Load() {
+		b.drop(topic, DropPaused, data)
+		return false
+	}
+	return true
+}
+
+// dropMessage drops m, the done callback of m is called with no called event
+func (b *Bus[T]) dropMessage(m *message[T], reason DropReason) {
+	b.drop(m.topic, reason, m.data)
+	m.finish(0)
+}
@@ -0,0 +1,83 @@
+package eventbus
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestOnDrop(t *testing.T) {
+	o := New[string]().Throttle("foo", time.Second).WithPausePolicy(PauseDrop, 0)
+	var drops []string
+	o.OnDrop(func(topic string, reason DropReason, data []string) {
+		drops = append(drops, fmt.Sprintf("%s:%s:%s", topic, reason, data[0]))
+	})
+	n := 0
+	o.On("foo", &N{&n, ""}).On("bar", &N{&n, ""})
+
+	o.Trigger("foo", "1").Trigger("foo", "2").Trigger("foo", "3")
+	o.PauseAll().Trigger("bar", "4").Broadcast("5").ResumeAll()
+	o.Shutdown(context.Background())
+	o.Trigger("bar", "6")
+
+	if n != 1 {
+		t.Errorf("The counter is %d instead of being %d", n, 1)
+	}
+	want := "[foo:throttle:2 foo:throttle:3 bar:paused:4 *:paused:5 bar:closed:6]"
+	if s := fmt.Sprint(drops); s != want {
+		t.Errorf("The drops are %s instead of being %s", s, want)
+	}
+	if c := o.DroppedCount(); c != 5 {
+		t.Errorf("The dropped count is %d instead of being %d", c, 5)
+	}
+	if c := o.ResetStats().DroppedCount(); c != 0 {
+		t.Errorf("The dropped count is %d instead of being %d", c, 0)
+	}
+}
+
+func TestOnDropDebounce(t *testing.T) {
+	o := New[string]().Debounce("foo", 10*time.Millisecond)
+	var drops []string
+	o.OnDrop(func(topic string, reason DropReason, data []string) {
+		drops = append(drops, fmt.Sprintf("%s:%s", reason, data[0]))
+	})
+	o.On("foo", &logEvent{})
+
+	o.Trigger("foo", "1").Trigger("foo", "2").Trigger("foo", "3")
+	o.Flush()
+	if s, want := fmt.Sprint(drops), "[debounce:1 debounce:2]"; s != want {
+		t.Errorf("The drops are %s instead of being %s", s, want)
+	}
+	if c := o.DroppedCount(); c != 2 {
+		t.Errorf("The dropped count is %d instead of being %d", c, 2)
+	}
+}
+
+func TestOnDropStopped(t *testing.T) {
+	o := New[string]()
+	var mu sync.Mutex
+	var drops []string
+	o.OnDrop(func(topic string, reason DropReason, data []string) {
+		mu.Lock()
+		defer mu.Unlock()
+		drops = append(drops, fmt.Sprintf("%s:%s", reason, data[0]))
+	})
+
+	// the messages queued behind the blocked call are dropped when the event is drained
+	e := &blockEvent{make(chan struct{}), make(chan struct{})}
+	o.OnSerial("foo", e).Trigger("foo", "1").Trigger("foo", "2").Trigger("foo", "3")
+	<-e.entered
+	o.Drain("foo")
+	close(e.release)
+	o.Flush()
+	mu.Lock()
+	if s, want := fmt.Sprint(drops), "[stopped:2 stopped:3]"; s != want {
+		t.Errorf("The drops are %s instead of being %s", s, want)
+	}
+	mu.Unlock()
+	if c := o.DroppedCount(); c != 2 {
+		t.Errorf("The dropped count is %d instead of being %d", c, 2)
+	}
+}
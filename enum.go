@@ -0,0 +1,57 @@
+package eventbus
+
+import (
+	"fmt"
+)
+
+// EnumBus struct, a Bus whose topics are the values of a comparable type like an int enum, so that the topics are
+// checked at compile time. The topics are converted by fmt.Sprint, the values must have distinct string forms
+type EnumBus[K comparable, T any] struct {
+	bus *Bus[T]
+}
+
+// NewEnum - return a new EnumBus object
+func NewEnum[K comparable, T any]() *EnumBus[K, T] {
+	return &EnumBus[K, T]{
+		bus: New[T](),
+	}
+}
+
+// Bus - return the underlying bus
+func (b *EnumBus[K, T]) Bus() *Bus[T] {
+	return b.bus
+}
+
+// On - register topic event
+func (b *EnumBus[K, T]) On(topic K, e ...Event[T]) *EnumBus[K, T] {
+	b.bus.On(fmt.Sprint(topic), e...)
+	return b
+}
+
+// Once - register once event
+func (b *EnumBus[K, T]) Once(topic K, e ...Event[T]) *EnumBus[K, T] {
+	b.bus.Once(fmt.Sprint(topic), e...)
+	return b
+}
+
+// Off - remove topic event
+func (b *EnumBus[K, T]) Off(topic K, e ...Event[T]) *EnumBus[K, T] {
+	b.bus.Off(fmt.Sprint(topic), e...)
+	return b
+}
+
+// Emit - dispatch event
+func (b *EnumBus[K, T]) Emit(topic K, msg ...T) *EnumBus[K, T] {
+	b.bus.Emit(fmt.Sprint(topic), msg...)
+	return b
+}
+
+// Trigger - dispatch event, same as Emit
+func (b *EnumBus[K, T]) Trigger(topic K, msg ...T) *EnumBus[K, T] {
+	return b.Emit(topic, msg...)
+}
+
+// Get - return the topic
+func (b *EnumBus[K, T]) Get(topic K) *Topic[T] {
+	return b.bus.Get(fmt.Sprint(topic))
+}
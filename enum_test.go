@@ -0,0 +1,38 @@
+package eventbus
+
+import (
+	"testing"
+)
+
+type orderState int
+
+const (
+	orderStateCreated orderState = iota
+	orderStatePaid
+	orderStateShipped
+)
+
+func TestEnumBus(t *testing.T) {
+	o := NewEnum[orderState, string]()
+	n := 0
+
+	onCreated := &N{&n, ""}
+	onPaid := &N{&n, ""}
+	o.On(orderStateCreated, onCreated).On(orderStatePaid, onPaid).Once(orderStateShipped, &N{&n, ""})
+
+	o.Trigger(orderStateCreated, "1").Trigger(orderStatePaid, "2").Emit(orderStateShipped).Emit(orderStateShipped)
+	if n != 3 {
+		t.Errorf("The counter is %d instead of being %d", n, 3)
+	}
+	if onCreated.s != "1" || onPaid.s != "2" {
+		t.Errorf("The messages are %s and %s instead of being %s and %s", onCreated.s, onPaid.s, "1", "2")
+	}
+
+	o.Off(orderStatePaid, onPaid).Get(orderStatePaid).Dispatch("3")
+	if onPaid.s != "2" {
+		t.Errorf("The last event name triggered is %s instead of being %s", onPaid.s, "2")
+	}
+	if c := o.Bus().TopicCount(); c != 1 {
+		t.Errorf("The topic count is %d instead of being %d", c, 1)
+	}
+}
@@ -0,0 +1,51 @@
+package eventbus
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"time"
+)
+
+// Envelope struct, the data of a message with the metadata assigned by TriggerEnv
+type Envelope[T any] struct {
+	// ID is generated for each TriggerEnv, it is empty for the other triggers
+	ID string
+	// Topic is the topic of the message
+	Topic string
+	// Time is the time of the TriggerEnv call, it is zero for the other triggers
+	Time time.Time
+	// Data is the data of the message
+	Data []T
+}
+
+// EnvelopeEvent interface, DispatchEnvelope is called instead of Dispatch with the envelope of the message
+type EnvelopeEvent[T any] interface {
+	Event[T]
+	DispatchEnvelope(env Envelope[T])
+}
+
+// TriggerEnv - dispatch event with an envelope holding a generated id and the current time,
+// all the EnvelopeEvent handlers of the dispatch receive the same envelope
+func (b *Bus[T]) TriggerEnv(topic string, msg ...T) *Bus[T] {
+	m := newMessage(topic, msg)
+	m.env = &Envelope[T]{ID: newEnvelopeID(), Topic: topic, Time: time.Now(), Data: msg}
+	b.dispatch(b.Get(topic), m)
+	return b
+}
+
+// envelope returns the envelope of m with data, the messages which are not triggered by TriggerEnv get an empty one
+func (m *message[T]) envelope(data []T) Envelope[T] {
+	if m.env == nil {
+		return Envelope[T]{Topic: m.topic, Data: data}
+	}
+	env := *m.env
+	env.Data = data
+	return env
+}
+
+// newEnvelopeID returns a random id of 32 hex characters
+func newEnvelopeID() string {
+	var id [16]byte
+	rand.Read(id[:])
+	return hex.EncodeToString(id[:])
+}
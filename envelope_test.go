@@ -0,0 +1,54 @@
+package eventbus
+
+import (
+	"testing"
+	"time"
+)
+
+type envelopeEvent struct {
+	envs []Envelope[string]
+}
+
+func (e *envelopeEvent) Dispatch(topic string, data ...string) {}
+
+func (e *envelopeEvent) DispatchEnvelope(env Envelope[string]) {
+	e.envs = append(e.envs, env)
+}
+
+func TestTriggerEnv(t *testing.T) {
+	o := New[string]()
+	n := 0
+
+	e1, e2 := &envelopeEvent{}, &envelopeEvent{}
+	onFoo := &N{&n, ""}
+	o.On("foo", e1, onFoo).On(ALL, e2)
+
+	start := time.Now()
+	o.TriggerEnv("foo", "test1")
+	env := e1.envs[0]
+	if len(env.ID) != 32 || env.Topic != "foo" || env.Data[0] != "test1" {
+		t.Errorf("The envelope is %+v", env)
+	}
+	if env.Time.Before(start) || env.Time.After(time.Now()) {
+		t.Errorf("The envelope time %s is not the trigger time", env.Time)
+	}
+	// the handlers of the same trigger receive the same envelope
+	if e2.envs[0].ID != env.ID || !e2.envs[0].Time.Equal(env.Time) {
+		t.Errorf("The envelope is %+v instead of being %+v", e2.envs[0], env)
+	}
+	// the plain handlers still receive the data
+	if onFoo.s != "test1" {
+		t.Errorf("The last event name triggered is %s instead of being %s", onFoo.s, "test1")
+	}
+
+	o.TriggerEnv("foo", "test2")
+	if e1.envs[1].ID == env.ID {
+		t.Error("The envelope id should be generated for each trigger")
+	}
+
+	// the other triggers deliver an envelope without id and time
+	o.Trigger("foo", "test3")
+	if env := e1.envs[2]; env.ID != "" || !env.Time.IsZero() || env.Data[0] != "test3" {
+		t.Errorf("The envelope is %+v", env)
+	}
+}
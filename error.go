@@ -0,0 +1,81 @@
+package eventbus
+
+import (
+	"context"
+	"log/slog"
+	"reflect"
+)
+
+// ErrEvent interface, the event whose Dispatch returns an error
+type ErrEvent[T any] interface {
+	Dispatch(topic string, data ...T) error
+}
+
+// ErrHandler struct, it adapts ErrEvent to Event. The ErrEvents registered by OnE are passed back as ErrHandler by
+// the methods returning the events like Drain, and registering it by On is the same as registering its ErrEvent by OnE.
+// The errors are passed to the error handler of the bus when the bus calls it, they are discarded if it is called otherwise
+type ErrHandler[T any] struct {
+	ErrEvent[T]
+}
+
+func (h ErrHandler[T]) Dispatch(topic string, data ...T) {
+	h.ErrEvent.Dispatch(topic, data...)
+}
+
+func (h ErrHandler[T]) OnStopCtx(ctx context.Context, topic string) {
+	callStop(ctx, h.ErrEvent, topic)
+}
+
+// OnError - set the callback which receives the errors returned by ErrEvent
+func (b *Bus[T]) OnError(fn func(topic string, e ErrEvent[T], err error)) *Bus[T] {
+	b.errHandler = fn
+	return b
+}
+
+// reportError passes the error of e to the logger and the error handler
+func (b *Bus[T]) reportError(topic string, e ErrEvent[T], err error) {
+	b.logError("eventbus: dispatch error", topic, e, slog.Any("error", err))
+	if b.errHandler != nil {
+		b.errHandler(topic, e, err)
+	}
+}
+
+// OnE - register topic ErrEvent
+func (b *Bus[T]) OnE(topic string, e ...ErrEvent[T]) *Bus[T] {
+	b.addErrEvents(topic, false, e)
+	return b
+}
+
+// OnceE - register once ErrEvent
+func (b *Bus[T]) OnceE(topic string, e ...ErrEvent[T]) *Bus[T] {
+	b.addErrEvents(topic, true, e)
+	return b
+}
+
+// OffE - remove topic ErrEvent
+func (b *Bus[T]) OffE(topic string, e ...ErrEvent[T]) *Bus[T] {
+	if len(e) == 0 {
+		return b.Off(topic)
+	}
+	tags := make([]reflect.Value, 0, len(e))
+	for _, v := range e {
+		tags = append(tags, reflect.ValueOf(v))
+	}
+	b.removeTags(topic, tags)
+	return b
+}
+
+func (b *Bus[T]) addErrEvents(topic string, isUnique bool, es []ErrEvent[T]) {
+	if len(es) == 0 {
+		return
+	}
+	events := make([]*event[T], 0, len(es))
+	for _, e := range es {
+		ev := newEvent[T](ErrHandler[T]{e}, topic, isUnique)
+		if isUnique {
+			b.guardOnce(ev)
+		}
+		events = append(events, ev)
+	}
+	b.insertEvents(topic, events)
+}
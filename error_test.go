@@ -0,0 +1,88 @@
+package eventbus
+
+import (
+	"errors"
+	"testing"
+)
+
+type failEvent struct {
+	n   int
+	err error
+}
+
+func (e *failEvent) Dispatch(topic string, data ...string) error {
+	e.n++
+	return e.err
+}
+
+func TestOnError(t *testing.T) {
+	o := New[string]()
+	n := 0
+	errs := []error{}
+	failed := []ErrEvent[string]{}
+
+	o.OnError(func(topic string, e ErrEvent[string], err error) {
+		errs = append(errs, err)
+		failed = append(failed, e)
+	})
+
+	fail := &failEvent{err: errors.New("fail")}
+	ok := &failEvent{}
+	o.OnE("foo", fail, ok).On("foo", &N{&n, ""})
+
+	o.Trigger("foo", "1").Trigger("foo", "2")
+	if fail.n != 2 || ok.n != 2 || n != 2 {
+		t.Errorf("The counters are %d, %d and %d instead of being %d", fail.n, ok.n, n, 2)
+	}
+	if len(errs) != 2 || errs[0] != fail.err || failed[0] != fail {
+		t.Errorf("The errors are %v instead of being %v", errs, []error{fail.err, fail.err})
+	}
+
+	o.OffE("foo", fail).Trigger("foo", "3")
+	if fail.n != 2 || len(errs) != 2 {
+		t.Errorf("The removed event is called %d times and the error count is %d", fail.n, len(errs))
+	}
+
+	once := &failEvent{err: errors.New("once")}
+	o.OnceE("bar", once).Trigger("bar").Trigger("bar")
+	if once.n != 1 || len(errs) != 3 || errs[2] != once.err {
+		t.Errorf("The once event is called %d times and the errors are %v", once.n, errs)
+	}
+}
+
+func TestErrHandler(t *testing.T) {
+	o := New[string]()
+	errs := 0
+	o.OnError(func(topic string, e ErrEvent[string], err error) {
+		errs++
+	})
+
+	// the ErrEvent is passed back as ErrHandler instead of the internal wrapper
+	fail := &failEvent{err: errors.New("fail")}
+	o.OnE("foo", fail)
+	drained := o.Drain("foo")
+	if len(drained) != 1 || drained[0] != (ErrHandler[string]{fail}) {
+		t.Errorf("The drained events are %v instead of being the ErrHandler of the ErrEvent", drained)
+	}
+
+	// registering it again is the same as OnE
+	o.On("foo", drained...).Trigger("foo")
+	if fail.n != 1 || errs != 1 {
+		t.Errorf("The counters are %d and %d instead of being %d", fail.n, errs, 1)
+	}
+	if !o.Has("foo", drained[0]) {
+		t.Error("The ErrHandler is not found")
+	}
+	if n := o.OffFunc("foo", func(e Event[string]) bool {
+		h, ok := e.(ErrHandler[string])
+		return ok && h.ErrEvent == fail
+	}); n != 1 {
+		t.Errorf("The counter is %d instead of being %d", n, 1)
+	}
+
+	once := &failEvent{}
+	consumed := o.OnceE("bar", once).TriggerCollect("bar")
+	if len(consumed) != 1 || consumed[0] != (ErrHandler[string]{once}) {
+		t.Errorf("The consumed events are %v instead of being the ErrHandler of the ErrEvent", consumed)
+	}
+}
@@ -1,7 +1,12 @@
 package eventbus
 
 import (
+	"context"
 	"reflect"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"time"
 )
 
 // ALL - The key use to listen all the topics
@@ -12,15 +17,291 @@ type Event[T any] interface {
 	Dispatch(topic string, data ...T)
 }
 
+// EventStop interface, OnStop is called after the event is removed from the topic
+type EventStop[T any] interface {
+	Event[T]
+	OnStop(topic string)
+}
+
+// EventStopCtx interface, OnStopCtx is called instead of OnStop after the event is removed from the topic,
+// ctx is done when the bus gives up waiting for the cleanup
+type EventStopCtx[T any] interface {
+	Event[T]
+	OnStopCtx(ctx context.Context, topic string)
+}
+
+// EventMeta interface, DispatchMeta is called instead of Dispatch with the metadata passed to TriggerMeta,
+// meta is nil if the message is dispatched without metadata
+type EventMeta[T any] interface {
+	Event[T]
+	DispatchMeta(meta map[string]string, topic string, data []T)
+}
+
+// SeqEvent interface, DispatchSeq is called instead of Dispatch with the sequence number of the message.
+// Every Trigger and Broadcast takes the next number of the bus, so the gaps or the reordering can be detected
+type SeqEvent[T any] interface {
+	Event[T]
+	DispatchSeq(seq uint64, topic string, data []T)
+}
+
+// DispatchN interface, DispatchN is called instead of Dispatch with the number of the events called by the dispatch,
+// including the ALL events
+type DispatchN[T any] interface {
+	Event[T]
+	DispatchN(topic string, data []T, total int)
+}
+
+// DispatchCoalesced interface, DispatchCoalesced is called instead of Dispatch with the number of the triggers
+// coalesced into the message by Debounce, it is 1 if the topic is not debounced
+type DispatchCoalesced[T any] interface {
+	Event[T]
+	DispatchCoalesced(topic string, data []T, coalesced int)
+}
+
+// SingleEvent interface, DispatchOne is called instead of Dispatch once for every element of the data
+type SingleEvent[T any] interface {
+	Event[T]
+	DispatchOne(topic string, data T)
+}
+
+// callStop calls OnStopCtx or OnStop of e if it implements one of them
+func callStop(ctx context.Context, e any, topic string) {
+	switch s := e.(type) {
+	case interface {
+		OnStopCtx(ctx context.Context, topic string)
+	}:
+		s.OnStopCtx(ctx, topic)
+	case interface{ OnStop(topic string) }:
+		s.OnStop(topic)
+	}
+}
+
 // event struct
 type event[T any] struct {
 	Event[T]
-	topic     string
+	topic     atomic.Pointer[string]
 	tag       reflect.Value
 	isUnique  bool
-	hasCalled uint32
+	guard     *onceGuard[T]
+	timer     *time.Timer
+	unwatch   func() bool
+	group     string
+	owned     bool
+	excludes  []string
+	suspended atomic.Bool
+	fallback  bool
+	priority  int
 }
 
 func newEvent[T any](e Event[T], topic string, isUnique bool) *event[T] {
-	return &event[T]{e, topic, reflect.ValueOf(e), isUnique, 0}
+	ev := &event[T]{Event: e, tag: tagOf(e), isUnique: isUnique}
+	ev.setTopic(topic)
+	return ev
+}
+
+// tagOf returns the identity of the handler, the internal wrappers are identified by the wrapped handler
+// and ErrHandler by its ErrEvent
+func tagOf[T any](e Event[T]) reflect.Value {
+	if w, ok := e.(wrapper[T]); ok {
+		return tagOf(w.unwrap())
+	}
+	if h, ok := e.(ErrHandler[T]); ok {
+		return reflect.ValueOf(h.ErrEvent)
+	}
+	return reflect.ValueOf(e)
+}
+
+// copy returns a copy of the event, the copy has no once guard
+func (e *event[T]) copy() *event[T] {
+	ev := &event[T]{
+		Event:    e.Event,
+		tag:      e.tag,
+		isUnique: e.isUnique,
+		timer:    e.timer,
+		unwatch:  e.unwatch,
+		group:    e.group,
+		owned:    e.owned,
+		excludes: e.excludes,
+		fallback: e.fallback,
+		priority: e.priority,
+	}
+	ev.topic.Store(e.topic.Load())
+	ev.suspended.Store(e.suspended.Load())
+	return ev
+}
+
+// withOnce returns a copy of the event which is a once event or not, the copy has no once guard
+func (e *event[T]) withOnce(isUnique bool) *event[T] {
+	ev := e.copy()
+	ev.isUnique = isUnique
+	return ev
+}
+
+// cloneTo returns a copy of the event for the bus, the internal wrapper is created again for it
+func (e *event[T]) cloneTo(b *Bus[T]) *event[T] {
+	ev := e.copy()
+	if c, ok := e.Event.(cloner[T]); ok {
+		ev.Event = c.clone(b)
+	}
+	return ev
+}
+
+// topicName returns the topic which the event is registered to
+func (e *event[T]) topicName() string {
+	return *e.topic.Load()
+}
+
+// setTopic changes the topic of the event, it is called when the event is moved by Rename
+func (e *event[T]) setTopic(topic string) {
+	e.topic.Store(&topic)
+}
+
+// wrapper interface, the internal event which calls the registered handler
+type wrapper[T any] interface {
+	unwrap() Event[T]
+}
+
+// cloner interface, the internal event which is bound to a bus or runs a goroutine, clone returns a new one
+// calling the same handler for the bus
+type cloner[T any] interface {
+	clone(b *Bus[T]) Event[T]
+}
+
+// relay interface, the internal event which passes the message to the handler, so that the handler receives it
+// by the interface it implements like it is called by the bus
+type relay[T any] interface {
+	relay(m *message[T], data []T)
+}
+
+// halter interface, the internal event which runs a goroutine, halt stops it without calling OnStop of the handler
+type halter interface {
+	halt()
+}
+
+// handler returns the registered event without the internal wrapper
+func (e *event[T]) handler() Event[T] {
+	if w, ok := e.Event.(wrapper[T]); ok {
+		return w.unwrap()
+	}
+	return e.Event
+}
+
+// halt stops the goroutine of the internal wrapper if it has one
+func (e *event[T]) halt() {
+	if h, ok := e.Event.(halter); ok {
+		h.halt()
+	}
+}
+
+// excluded returns whether the messages of the topic are excluded from the event
+func (e *event[T]) excluded(topic string) bool {
+	for _, prefix := range e.excludes {
+		if strings.HasPrefix(topic, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// stop stops the timer, the context watcher and the goroutine of the wrapper of the event which is removed
+func (e *event[T]) stop() {
+	if e.timer != nil {
+		e.timer.Stop()
+	}
+	if e.unwatch != nil {
+		e.unwatch()
+	}
+	e.halt()
+}
+
+// onceGuard is shared by the pending once events of the same handler,
+// so that the handler is called only once even if it is reachable by several topics
+type onceGuard[T any] struct {
+	hasCalled uint32
+	events    []*event[T]
+}
+
+// message struct, the data dispatched to the events with its delivery state
+type message[T any] struct {
+	topic     string
+	data      []T
+	route     []*Bus[T]
+	coalesced int
+	meta      map[string]string
+	seq       uint64
+	called    map[reflect.Value]bool
+	queued    bool
+	resumed   bool
+	direct    bool
+	total     int
+	collect   bool
+	pin       bool
+	consumed  []Event[T]
+	report    *DispatchReport
+	then      func(handlers int)
+	env       *Envelope[T]
+	pinned    []*event[T]
+	value     [1]T
+}
+
+// DispatchReport struct, the report of a dispatch returned by TriggerReport
+type DispatchReport struct {
+	// TopicHandlers is the number of the called topic events
+	TopicHandlers int
+	// AsteriskHandlers is the number of the called ALL events
+	AsteriskHandlers int
+	// OnceRemoved is the number of the called once events which are removed
+	OnceRemoved int
+}
+
+// add counts the called events of a topic
+func (r *DispatchReport) add(called, onces int, asterisk bool) {
+	if asterisk {
+		r.AsteriskHandlers += called
+	} else {
+		r.TopicHandlers += called
+	}
+	r.OnceRemoved += onces
+}
+
+// registered returns the events pinned by TriggerIf which are still in events, events is returned as it is
+// if nothing is pinned
+func (m *message[T]) registered(events []*event[T]) []*event[T] {
+	if !m.pin {
+		return events
+	}
+	var pinned []*event[T]
+	for _, e := range m.pinned {
+		if slices.Contains(events, e) {
+			pinned = append(pinned, e)
+		}
+	}
+	return pinned
+}
+
+func newMessage[T any](topic string, data []T) *message[T] {
+	return &message[T]{topic: topic, data: data}
+}
+
+// newValueMessage returns the message of a single value, the data is backed by the message itself
+func newValueMessage[T any](topic string, v T) *message[T] {
+	m := &message[T]{topic: topic}
+	m.value[0] = v
+	m.data = m.value[:]
+	return m
+}
+
+// finish calls the done callback of the message with the number of the called events
+func (m *message[T]) finish(n int) {
+	if m.then != nil {
+		m.then(n)
+	}
+}
+
+// markCalled records that the handler of tag is called by the message
+func (m *message[T]) markCalled(tag reflect.Value) {
+	if m.called == nil {
+		m.called = make(map[reflect.Value]bool)
+	}
+	m.called[tag] = true
 }
@@ -0,0 +1,39 @@
+package eventbus
+
+// defaultBus is the bus of the package level helpers, its messages are any typed
+var defaultBus = New[any]()
+
+// Default - return the bus of the package level helpers, it is used to configure it like AllowAsterisk
+func Default() *Bus[any] {
+	return defaultBus
+}
+
+// On - register topic event to the default bus
+func On(topic string, e ...Event[any]) *Bus[any] {
+	return defaultBus.On(topic, e...)
+}
+
+// Once - register once event to the default bus
+func Once(topic string, e ...Event[any]) *Bus[any] {
+	return defaultBus.Once(topic, e...)
+}
+
+// Off - remove topic event from the default bus
+func Off(topic string, e ...Event[any]) *Bus[any] {
+	return defaultBus.Off(topic, e...)
+}
+
+// Emit - dispatch msg to the topic events of the default bus
+func Emit(topic string, msg ...any) *Bus[any] {
+	return defaultBus.Emit(topic, msg...)
+}
+
+// Trigger - dispatch event to the default bus, same as Emit
+func Trigger(topic string, msg ...any) *Bus[any] {
+	return defaultBus.Trigger(topic, msg...)
+}
+
+// Broadcast - dispatch msg to every topic of the default bus once
+func Broadcast(msg ...any) *Bus[any] {
+	return defaultBus.Broadcast(msg...)
+}
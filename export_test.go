@@ -0,0 +1,44 @@
+package eventbus
+
+import (
+	"testing"
+)
+
+type anyEvent struct {
+	n    int
+	data []any
+}
+
+func (e *anyEvent) Dispatch(topic string, data ...any) {
+	e.n++
+	e.data = data
+}
+
+func TestDefault(t *testing.T) {
+	defer Default().CleanSync().AllowAsterisk(true)
+
+	onFoo := &anyEvent{}
+	onAll := &anyEvent{}
+	On("foo", onFoo).On(ALL, onAll)
+	Once("bar", onFoo)
+
+	Trigger("foo", 1, "a")
+	if onFoo.n != 1 || onAll.n != 1 {
+		t.Errorf("The counters are %d and %d instead of being %d", onFoo.n, onAll.n, 1)
+	}
+	if len(onAll.data) != 2 || onAll.data[0] != 1 || onAll.data[1] != "a" {
+		t.Errorf("The data is %v instead of being %v", onAll.data, []any{1, "a"})
+	}
+
+	Default().AllowAsterisk(false)
+	Emit("bar").Trigger("bar")
+	if onFoo.n != 2 || onAll.n != 1 {
+		t.Errorf("The counters are %d and %d instead of being %d and %d", onFoo.n, onAll.n, 2, 1)
+	}
+
+	Off("foo", onFoo)
+	Broadcast()
+	if onFoo.n != 2 || onAll.n != 2 {
+		t.Errorf("The counters are %d and %d instead of being %d and %d", onFoo.n, onAll.n, 2, 2)
+	}
+}
@@ -0,0 +1,32 @@
+package eventbus
+
+// OnFallback - register topic event which is called only while the topic has no other event, it provides
+// the default handling which is superseded once a normal event subscribes and resumes after it is removed.
+// The ALL events don't supersede it, use Off to unsubscribe it
+func (b *Bus[T]) OnFallback(topic string, e Event[T]) *Bus[T] {
+	ev := newEvent(e, topic, false)
+	ev.fallback = true
+	b.insertEvents(topic, []*event[T]{ev})
+	return b
+}
+
+// fallbacks drops the fallback events if there is any other event, events is returned as it is
+// if there is no fallback event
+func fallbacks[T any](events []*event[T]) []*event[T] {
+	n := 0
+	for _, e := range events {
+		if e.fallback {
+			n++
+		}
+	}
+	if n == 0 || n == len(events) {
+		return events
+	}
+	normal := make([]*event[T], 0, len(events)-n)
+	for _, e := range events {
+		if !e.fallback {
+			normal = append(normal, e)
+		}
+	}
+	return normal
+}
@@ -0,0 +1,43 @@
+package eventbus
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestOnFallback(t *testing.T) {
+	o := New[string]()
+
+	fallback := &CaptureEvent[string]{}
+	normal := &CaptureEvent[string]{}
+	all := &CaptureEvent[string]{}
+
+	// the fallback is called alone, the ALL events don't supersede it
+	o.OnFallback("foo", fallback).On(ALL, all).Trigger("foo", "1")
+	if n := fallback.Len(); n != 1 {
+		t.Errorf("The counter is %d instead of being %d", n, 1)
+	}
+
+	// the normal event supersedes the fallback
+	o.On("foo", normal).Trigger("foo", "2").Broadcast("3")
+	if n := fallback.Len(); n != 1 {
+		t.Errorf("The counter is %d instead of being %d", n, 1)
+	}
+	if calls := normal.Calls(); fmt.Sprint(calls) != "[{foo [2]} {foo [3]}]" {
+		t.Errorf("The calls are %v instead of being [{foo [2]} {foo [3]}]", calls)
+	}
+
+	// the fallback resumes after the normal event is removed
+	o.Off("foo", normal).Trigger("foo", "4")
+	if calls := fallback.Calls(); fmt.Sprint(calls) != "[{foo [1]} {foo [4]}]" {
+		t.Errorf("The calls are %v instead of being [{foo [1]} {foo [4]}]", calls)
+	}
+	if n := all.Len(); n != 4 {
+		t.Errorf("The ALL counter is %d instead of being %d", n, 4)
+	}
+
+	o.Off("foo", fallback).Trigger("foo", "5")
+	if n := fallback.Len(); n != 2 {
+		t.Errorf("The counter is %d instead of being %d", n, 2)
+	}
+}
@@ -1,5 +1,5 @@
 module github.com/lockp111/go-eventbus
 
-go 1.21.0
+go 1.24.0
 
 require github.com/lockp111/go-cmap v1.3.0
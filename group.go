@@ -0,0 +1,60 @@
+package eventbus
+
+import (
+	"sync/atomic"
+)
+
+// OnGroup - register topic event to the group, each message of the topic is dispatched to only one event
+// of the group in round robin, the events outside any group still receive every message
+func (b *Bus[T]) OnGroup(topic, group string, e Event[T]) *Bus[T] {
+	ev := newEvent(e, topic, false)
+	ev.group = group
+	b.insertEvents(topic, []*event[T]{ev})
+	return b
+}
+
+// cursor returns the round robin cursor of the group
+func (t *Topic[T]) cursor(group string) *atomic.Uint64 {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.cursors == nil {
+		t.cursors = make(map[string]*atomic.Uint64)
+	}
+	c, ok := t.cursors[group]
+	if !ok {
+		c = &atomic.Uint64{}
+		t.cursors[group] = c
+	}
+	return c
+}
+
+// balance keeps the next event of each group and the events outside any group, events is returned as it is
+// if there is no group. The suspended members and the members excluding the topic of the message don't take a turn
+func (t *Topic[T]) balance(events []*event[T], topic string) []*event[T] {
+	var groups map[string][]*event[T]
+	for _, e := range events {
+		if e.group == "" || e.suspended.Load() || e.excluded(topic) {
+			continue
+		}
+		if groups == nil {
+			groups = make(map[string][]*event[T])
+		}
+		groups[e.group] = append(groups[e.group], e)
+	}
+	if groups == nil {
+		return events
+	}
+
+	next := make(map[*event[T]]bool, len(groups))
+	for group, members := range groups {
+		i := (t.cursor(group).Add(1) - 1) % uint64(len(members))
+		next[members[i]] = true
+	}
+	balanced := make([]*event[T], 0, len(events))
+	for _, e := range events {
+		if e.group == "" || next[e] {
+			balanced = append(balanced, e)
+		}
+	}
+	return balanced
+}
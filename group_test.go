@@ -0,0 +1,65 @@
+package eventbus
+
+import (
+	"testing"
+)
+
+func TestOnGroup(t *testing.T) {
+	o := New[string]()
+	n := 0
+
+	workers := []*N{{new(int), ""}, {new(int), ""}, {new(int), ""}}
+	for _, w := range workers {
+		o.OnGroup("job", "workers", w)
+	}
+	o.On("job", &N{&n, ""}).OnGroup("job", "audit", &N{&n, ""})
+
+	for i := 0; i < 7; i++ {
+		o.Trigger("job")
+		for j, w := range workers {
+			// the workers are served in round robin
+			expected := i / 3
+			if j <= i%3 {
+				expected++
+			}
+			if *w.i != expected {
+				t.Errorf("The counter of the worker %d is %d instead of being %d after %d triggers", j, *w.i, expected, i+1)
+			}
+		}
+	}
+	if n != 14 {
+		t.Errorf("The counter is %d instead of being %d", n, 14)
+	}
+
+	// the removed member is not served anymore
+	o.Off("job", workers[0], workers[1])
+	o.Trigger("job").Trigger("job")
+	if *workers[2].i != 4 {
+		t.Errorf("The counter is %d instead of being %d", *workers[2].i, 4)
+	}
+}
+
+func TestOnGroupSuspend(t *testing.T) {
+	o := New[string]()
+	n := 0
+
+	a, b := &N{&n, ""}, &N{new(int), ""}
+	o.OnGroup("job", "workers", a).OnGroup("job", "workers", b).Suspend("job", b)
+	// the suspended member doesn't take a turn, so every message reaches the other one
+	for i := 0; i < 4; i++ {
+		o.Trigger("job")
+	}
+	if n != 4 || *b.i != 0 {
+		t.Errorf("The counters are %d and %d instead of being %d and %d", n, *b.i, 4, 0)
+	}
+
+	// the member excluding the topic doesn't take a turn either
+	c := &N{new(int), ""}
+	o.OnGroup("$job", "workers", &N{&n, ""})
+	o.OnGroup("$job", "workers", c)
+	o.Get("$job").events[1].excludes = []string{"$"}
+	o.Trigger("$job").Trigger("$job")
+	if n != 6 || *c.i != 0 {
+		t.Errorf("The counters are %d and %d instead of being %d and %d", n, *c.i, 6, 0)
+	}
+}
@@ -0,0 +1,146 @@
+package eventbus
+
+import (
+	"context"
+	"slices"
+	"sync"
+)
+
+// history struct, a ring buffer of the last messages of a topic
+type history[T any] struct {
+	mu    sync.Mutex
+	size  int
+	start int
+	msgs  [][]T
+}
+
+func newHistory[T any](size int) *history[T] {
+	return &history[T]{
+		size: size,
+		msgs: make([][]T, 0, size),
+	}
+}
+
+// push records data, the oldest message is dropped when the buffer is full
+func (h *history[T]) push(data []T) {
+	data = slices.Clone(data)
+	if len(h.msgs) < h.size {
+		h.msgs = append(h.msgs, data)
+		return
+	}
+	h.msgs[h.start] = data
+	h.start = (h.start + 1) % h.size
+}
+
+// messages returns the recorded messages from the oldest to the newest
+func (h *history[T]) messages() [][]T {
+	msgs := make([][]T, 0, len(h.msgs))
+	msgs = append(msgs, h.msgs[h.start:]...)
+	return append(msgs, h.msgs[:h.start]...)
+}
+
+// replayEvent struct, it holds the live messages until the history is replayed
+type replayEvent[T any] struct {
+	Event[T]
+	bus       *Bus[T]
+	mu        sync.Mutex
+	replaying bool
+	pending   []*message[T]
+}
+
+func (r *replayEvent[T]) Dispatch(topic string, data ...T) {
+	r.relay(newMessage(topic, data), data)
+}
+
+func (r *replayEvent[T]) relay(m *message[T], data []T) {
+	r.mu.Lock()
+	if r.replaying {
+		// the message is copied with the data passed to the handler, which may be a copy of the payload
+		held := *m
+		held.data = data
+		r.pending = append(r.pending, &held)
+		r.mu.Unlock()
+		return
+	}
+	r.mu.Unlock()
+	r.bus.deliver(r.Event, m, data)
+}
+
+func (r *replayEvent[T]) unwrap() Event[T] {
+	return r.Event
+}
+
+// clone returns the handler, the clone has no history to replay
+func (r *replayEvent[T]) clone(b *Bus[T]) Event[T] {
+	return r.Event
+}
+
+func (r *replayEvent[T]) OnStopCtx(ctx context.Context, topic string) {
+	callStop(ctx, r.Event, topic)
+}
+
+// replay dispatches the history, then the live messages received meanwhile, and switches to live.
+// The panics are recovered like the ones of a dispatch, the event switches to live even if a panic is not recovered
+func (r *replayEvent[T]) replay(topic string, msgs [][]T) {
+	defer func() {
+		r.mu.Lock()
+		r.replaying = false
+		r.pending = nil
+		r.mu.Unlock()
+	}()
+	for _, data := range msgs {
+		r.dispatch(newMessage(topic, data))
+	}
+	for {
+		r.mu.Lock()
+		pending := r.pending
+		r.pending = nil
+		if len(pending) == 0 {
+			r.replaying = false
+			r.mu.Unlock()
+			return
+		}
+		r.mu.Unlock()
+		for _, m := range pending {
+			r.dispatch(m)
+		}
+	}
+}
+
+// dispatch calls the handler with a replayed or a held message
+func (r *replayEvent[T]) dispatch(m *message[T]) {
+	defer r.bus.recoverPanic(m.topic, r.Event)
+	r.bus.deliver(r.Event, m, m.data)
+}
+
+// WithHistory - retain the last n messages emitted to the topic for OnReplay, n <= 0 disables it
+func (b *Bus[T]) WithHistory(topic string, n int) *Bus[T] {
+	if n <= 0 {
+		b.histories.remove(topic)
+		return b
+	}
+	b.histories.store().Set(topic, newHistory[T](n))
+	return b
+}
+
+// OnReplay - register topic event, the retained messages are dispatched to it before the live ones
+func (b *Bus[T]) OnReplay(topic string, e Event[T]) *Bus[T] {
+	h, ok := b.histories.get(topic)
+	if !ok {
+		return b.On(topic, e)
+	}
+
+	r := &replayEvent[T]{Event: e, bus: b, replaying: true}
+	ev := newEvent[T](r, topic, false)
+
+	// the messages recorded before the registration are replayed, the later ones are dispatched live
+	h.mu.Lock()
+	msgs := h.messages()
+	_, err := b.insertEvents(topic, []*event[T]{ev})
+	h.mu.Unlock()
+
+	if err == nil {
+		r.replay(topic, msgs)
+	}
+	return b
+}
@@ -0,0 +1,137 @@
+package eventbus
+
+import (
+	"fmt"
+	"strconv"
+	"sync"
+	"testing"
+)
+
+type logEvent struct {
+	mu   sync.Mutex
+	logs []string
+}
+
+func (e *logEvent) Dispatch(topic string, data ...string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.logs = append(e.logs, data...)
+}
+
+func (e *logEvent) Logs() []string {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return append([]string(nil), e.logs...)
+}
+
+func TestReplay(t *testing.T) {
+	o := New[string]().WithHistory("foo", 3)
+
+	for i := 1; i <= 5; i++ {
+		o.Trigger("foo", strconv.Itoa(i))
+	}
+
+	onFoo := &logEvent{}
+	o.OnReplay("foo", onFoo)
+	if logs := fmt.Sprint(onFoo.Logs()); logs != "[3 4 5]" {
+		t.Errorf("The replayed messages are %s instead of being %s", logs, "[3 4 5]")
+	}
+
+	o.Trigger("foo", "6")
+	if logs := fmt.Sprint(onFoo.Logs()); logs != "[3 4 5 6]" {
+		t.Errorf("The messages are %s instead of being %s", logs, "[3 4 5 6]")
+	}
+
+	// the topic without history is an ordinary subscription
+	onBar := &logEvent{}
+	o.Trigger("bar", "1").OnReplay("bar", onBar).Trigger("bar", "2")
+	if logs := fmt.Sprint(onBar.Logs()); logs != "[2]" {
+		t.Errorf("The messages are %s instead of being %s", logs, "[2]")
+	}
+
+	o.Off("foo", onFoo).Trigger("foo", "7")
+	if logs := fmt.Sprint(onFoo.Logs()); logs != "[3 4 5 6]" {
+		t.Errorf("The messages are %s instead of being %s", logs, "[3 4 5 6]")
+	}
+}
+
+func TestReplayHandoff(t *testing.T) {
+	const count = 1000
+	o := New[string]().WithHistory("foo", count)
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < count; i++ {
+			o.Trigger("foo", strconv.Itoa(i))
+		}
+	}()
+
+	onFoo := &logEvent{}
+	o.OnReplay("foo", onFoo)
+	wg.Wait()
+
+	logs := onFoo.Logs()
+	if len(logs) != count {
+		t.Fatalf("The message count is %d instead of being %d", len(logs), count)
+	}
+	for i, s := range logs {
+		if s != strconv.Itoa(i) {
+			t.Fatalf("The message %d is %s instead of being %d", i, s, i)
+		}
+	}
+}
+
+// panicLogEvent struct, it logs the messages and panics on "panic"
+type panicLogEvent struct {
+	logEvent
+}
+
+func (e *panicLogEvent) Dispatch(topic string, data ...string) {
+	if data[0] == "panic" {
+		panic(data[0])
+	}
+	e.logEvent.Dispatch(topic, data...)
+}
+
+func TestReplayPanic(t *testing.T) {
+	o := New[string]().WithHistory("foo", 3)
+	o.Trigger("foo", "1").Trigger("foo", "panic").Trigger("foo", "2")
+
+	// the recovered panic doesn't stop the replay
+	panics := 0
+	o.OnPanic(func(topic string, e Event[string], v any) {
+		panics++
+	})
+	onFoo := &panicLogEvent{}
+	o.OnReplay("foo", onFoo).Trigger("foo", "3")
+	if logs := fmt.Sprint(onFoo.Logs()); logs != "[1 2 3]" || panics != 1 {
+		t.Errorf("The messages are %s with %d panics instead of being %s with %d panics", logs, panics, "[1 2 3]", 1)
+	}
+
+	// the live panic is passed with the registered event instead of the internal wrapper
+	var handler Event[string]
+	o.OnPanic(func(topic string, e Event[string], v any) {
+		handler = e
+	}).Trigger("foo", "panic")
+	if handler != onFoo {
+		t.Errorf("The event is %T instead of being the registered event", handler)
+	}
+
+	// the event switches to live even if the panic is not recovered
+	o.OnPanic(nil)
+	onBar := &panicLogEvent{}
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Error("The panic should not be recovered")
+			}
+		}()
+		o.OnReplay("foo", onBar)
+	}()
+	o.Trigger("foo", "4")
+	if logs := fmt.Sprint(onBar.Logs()); logs != "[2 3 4]" {
+		t.Errorf("The messages are %s instead of being %s", logs, "[2 3 4]")
+	}
+}
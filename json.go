@@ -0,0 +1,49 @@
+package eventbus
+
+import (
+	"encoding/json"
+)
+
+// JSONEvent struct, it decodes the JSON payloads and passes them to the callback
+type JSONEvent[U any] struct {
+	fn    func(topic string, v U) error
+	onErr func(topic string, err error)
+}
+
+// OnError - set the callback which receives the decoding errors and the errors returned by the callback
+func (e *JSONEvent[U]) OnError(fn func(topic string, err error)) *JSONEvent[U] {
+	e.onErr = fn
+	return e
+}
+
+// Dispatch - decode each payload and pass it to the callback
+func (e *JSONEvent[U]) Dispatch(topic string, data ...[]byte) {
+	for _, b := range data {
+		var v U
+		err := json.Unmarshal(b, &v)
+		if err == nil {
+			err = e.fn(topic, v)
+		}
+		if err != nil && e.onErr != nil {
+			e.onErr(topic, err)
+		}
+	}
+}
+
+// PublishJSON - encode v to JSON and trigger it
+func PublishJSON[U any](b *Bus[[]byte], topic string, v U) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	b.Trigger(topic, data)
+	return nil
+}
+
+// OnJSON - register a callback which receives the JSON payloads decoded to U,
+// the returned event is used to set the error callback and to remove by Off
+func OnJSON[U any](b *Bus[[]byte], topic string, fn func(topic string, v U) error) *JSONEvent[U] {
+	e := &JSONEvent[U]{fn: fn}
+	b.On(topic, e)
+	return e
+}
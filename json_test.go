@@ -0,0 +1,45 @@
+package eventbus
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestJSON(t *testing.T) {
+	o := New[[]byte]()
+	orders := []order{}
+	errs := []error{}
+
+	e := OnJSON(o, "order", func(topic string, v order) error {
+		if v.ID == 0 {
+			return errors.New("invalid id")
+		}
+		orders = append(orders, v)
+		return nil
+	}).OnError(func(topic string, err error) {
+		errs = append(errs, err)
+	})
+
+	if err := PublishJSON(o, "order", order{1, true, "foo"}); err != nil {
+		t.Fatal(err)
+	}
+	if len(orders) != 1 || orders[0] != (order{1, true, "foo"}) {
+		t.Errorf("The orders are %v instead of being %v", orders, []order{{1, true, "foo"}})
+	}
+
+	o.Trigger("order", []byte("{"))
+	PublishJSON(o, "order", order{})
+	if len(errs) != 2 {
+		t.Errorf("The error count is %d instead of being %d", len(errs), 2)
+	}
+
+	if err := PublishJSON(o, "order", make(chan int)); err == nil {
+		t.Error("The unsupported value should not be published")
+	}
+
+	o.Off("order", e)
+	PublishJSON(o, "order", order{2, false, "bar"})
+	if len(orders) != 1 {
+		t.Errorf("The order count is %d instead of being %d", len(orders), 1)
+	}
+}
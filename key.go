@@ -0,0 +1,71 @@
+package eventbus
+
+import (
+	"hash/fnv"
+)
+
+// DefaultKeyWorkers - the default number of the workers of TriggerKey
+const DefaultKeyWorkers = 16
+
+// keyedMessage struct, a message waiting in the queue of a key worker
+type keyedMessage[T any] struct {
+	m    *message[T]
+	done func()
+}
+
+// WithKeyWorkers - set the number of the workers of TriggerKey, default is DefaultKeyWorkers.
+// It must be called before the first TriggerKey
+func (b *Bus[T]) WithKeyWorkers(n int) *Bus[T] {
+	b.keyWorkers = n
+	return b
+}
+
+// TriggerKey - dispatch event on the worker selected by the key, the messages of the same key are dispatched
+// one by one in order while the messages of different keys may be dispatched in parallel. It returns once the
+// message is queued, it blocks while the queue is full. Use Flush to wait for the queued messages.
+// The message is dropped after Shutdown
+func (b *Bus[T]) TriggerKey(key string, topic string, msg ...T) *Bus[T] {
+	m := newMessage(topic, msg)
+	// the queues are closed by Shutdown with the write lock
+	b.keyMu.RLock()
+	defer b.keyMu.RUnlock()
+	if b.closed.Load() {
+		b.dropMessage(m, DropClosed)
+		return b
+	}
+	b.keyOnce.Do(b.startKeyWorkers)
+	h := fnv.New32a()
+	h.Write([]byte(key))
+	m.queued = true
+	b.keyQueues[h.Sum32()%uint32(len(b.keyQueues))] <- keyedMessage[T]{m, b.pending.add()}
+	return b
+}
+
+// stopKeyWorkers closes the queues of TriggerKey, the workers exit after the queued messages are dispatched
+func (b *Bus[T]) stopKeyWorkers() {
+	b.keyMu.Lock()
+	defer b.keyMu.Unlock()
+	for _, queue := range b.keyQueues {
+		close(queue)
+	}
+	b.keyQueues = nil
+}
+
+// startKeyWorkers starts the workers of TriggerKey, they live until Shutdown
+func (b *Bus[T]) startKeyWorkers() {
+	n := b.keyWorkers
+	if n <= 0 {
+		n = DefaultKeyWorkers
+	}
+	b.keyQueues = make([]chan keyedMessage[T], n)
+	for i := range b.keyQueues {
+		queue := make(chan keyedMessage[T], 1024)
+		b.keyQueues[i] = queue
+		go func() {
+			for k := range queue {
+				b.dispatch(b.Get(k.m.topic), k.m)
+				k.done()
+			}
+		}()
+	}
+}
@@ -0,0 +1,114 @@
+package eventbus
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"sync"
+	"testing"
+	"time"
+)
+
+type keyedEvent struct {
+	mu   sync.Mutex
+	seen map[string][]int
+}
+
+func (e *keyedEvent) Dispatch(topic string, data ...string) {
+	var (
+		key string
+		i   int
+	)
+	fmt.Sscanf(data[0], "%s %d", &key, &i)
+	e.mu.Lock()
+	e.seen[key] = append(e.seen[key], i)
+	e.mu.Unlock()
+}
+
+func TestTriggerKey(t *testing.T) {
+	o := New[string]().WithKeyWorkers(4)
+	e := &keyedEvent{seen: map[string][]int{}}
+	o.On("foo", e)
+
+	keys := []string{"a", "b", "c", "d", "e", "f"}
+	const n = 200
+	for i := 0; i < n; i++ {
+		for _, k := range keys {
+			o.TriggerKey(k, "foo", fmt.Sprintf("%s %d", k, i))
+		}
+	}
+	o.Flush()
+
+	for _, k := range keys {
+		seen := e.seen[k]
+		if len(seen) != n {
+			t.Fatalf("The counter is %d instead of being %d", len(seen), n)
+		}
+		for i, v := range seen {
+			if v != i {
+				t.Fatalf("The message %d of key %s is delivered at %d", v, k, i)
+			}
+		}
+	}
+}
+
+func TestTriggerKeyParallel(t *testing.T) {
+	o := New[string]()
+	e := &keyedEvent{seen: map[string][]int{}}
+	o.On("foo", e)
+
+	var wg sync.WaitGroup
+	for _, k := range []string{"a", "b", "c"} {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				o.TriggerKey(k, "foo", fmt.Sprintf("%s %d", k, i))
+			}
+		}()
+	}
+	wg.Wait()
+	o.Flush()
+
+	for k, seen := range e.seen {
+		for i, v := range seen {
+			if v != i {
+				t.Fatalf("The message %d of key %s is delivered at %d", v, k, i)
+			}
+		}
+	}
+}
+
+func TestTriggerKeyShutdown(t *testing.T) {
+	o := New[string]().WithKeyWorkers(4)
+	e := &keyedEvent{seen: map[string][]int{}}
+	var drops []string
+	o.OnDrop(func(topic string, reason DropReason, data []string) {
+		drops = append(drops, fmt.Sprintf("%s:%s", reason, data[0]))
+	}).OnNoListener(func(topic string, data []string) {
+		t.Errorf("The message %v is dispatched after the shutdown", data)
+	}).On("foo", e)
+
+	base := runtime.NumGoroutine()
+	o.TriggerKey("a", "foo", "a 0")
+	if err := o.Shutdown(context.Background()); err != nil {
+		t.Errorf("The error is %v instead of being nil", err)
+	}
+	if seen := e.seen["a"]; len(seen) != 1 {
+		t.Errorf("The queued message is not delivered: %v", seen)
+	}
+
+	// the messages are dropped after the shutdown and the workers exit
+	o.TriggerKey("a", "foo", "a 1")
+	<-o.TriggerAsync("foo", "a 2")
+	if s := fmt.Sprint(drops); s != "[closed:a 1 closed:a 2]" {
+		t.Errorf("The drops are %s instead of being [closed:a 1 closed:a 2]", s)
+	}
+	deadline := time.Now().Add(time.Second)
+	for runtime.NumGoroutine() > base && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if n := runtime.NumGoroutine(); n > base {
+		t.Errorf("The goroutine count is %d instead of being at most %d", n, base)
+	}
+}
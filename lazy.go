@@ -0,0 +1,59 @@
+package eventbus
+
+import (
+	"sync"
+	"sync/atomic"
+
+	"github.com/lockp111/go-cmap"
+)
+
+// lazyMap struct, a concurrent map of the topics which is allocated on the first write,
+// so that a bus never using the feature of the map doesn't pay for it. The reads of an unallocated map find nothing
+type lazyMap[V any] struct {
+	mu sync.Mutex
+	m  atomic.Pointer[cmap.ConcurrentMap[string, V]]
+}
+
+// get returns the value of the topic
+func (l *lazyMap[V]) get(topic string) (v V, ok bool) {
+	if m := l.m.Load(); m != nil {
+		return m.Get(topic)
+	}
+	return
+}
+
+// has returns whether the topic is stored
+func (l *lazyMap[V]) has(topic string) bool {
+	m := l.m.Load()
+	return m != nil && m.Has(topic)
+}
+
+// values returns the values of all the topics
+func (l *lazyMap[V]) values() []V {
+	if m := l.m.Load(); m != nil {
+		return m.Values()
+	}
+	return nil
+}
+
+// remove removes the topic
+func (l *lazyMap[V]) remove(topic string) {
+	if m := l.m.Load(); m != nil {
+		m.Remove(topic)
+	}
+}
+
+// store returns the map and allocates it on the first call
+func (l *lazyMap[V]) store() *cmap.ConcurrentMap[string, V] {
+	if m := l.m.Load(); m != nil {
+		return m
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if m := l.m.Load(); m != nil {
+		return m
+	}
+	m := cmap.New[V]()
+	l.m.Store(&m)
+	return &m
+}
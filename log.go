@@ -0,0 +1,28 @@
+package eventbus
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+)
+
+// WithLogger - set the logger which records the subscriptions and the unsubscriptions at debug level,
+// and the errors of ErrEvent and the panics recovered by OnPanic at error level. Nothing is logged by default
+func (b *Bus[T]) WithLogger(logger *slog.Logger) *Bus[T] {
+	b.logger = logger
+	return b
+}
+
+// logLifecycle records the subscribed or unsubscribed events of the topic
+func (b *Bus[T]) logLifecycle(msg, topic string, count int) {
+	if b.logger != nil && count > 0 {
+		b.logger.LogAttrs(context.Background(), slog.LevelDebug, msg, slog.String("topic", topic), slog.Int("count", count))
+	}
+}
+
+// logError records the error or the panic of the event
+func (b *Bus[T]) logError(msg, topic string, e any, attr slog.Attr) {
+	if b.logger != nil {
+		b.logger.LogAttrs(context.Background(), slog.LevelError, msg, slog.String("topic", topic), slog.String("event", fmt.Sprintf("%T", e)), attr)
+	}
+}
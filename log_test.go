@@ -0,0 +1,87 @@
+package eventbus
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+	"testing"
+)
+
+// captureHandler records the log records
+type captureHandler struct {
+	mu      sync.Mutex
+	records []slog.Record
+}
+
+func (h *captureHandler) Enabled(context.Context, slog.Level) bool { return true }
+
+func (h *captureHandler) Handle(_ context.Context, r slog.Record) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.records = append(h.records, r)
+	return nil
+}
+
+func (h *captureHandler) WithAttrs([]slog.Attr) slog.Handler { return h }
+
+func (h *captureHandler) WithGroup(string) slog.Handler { return h }
+
+// attrs returns the message and the attributes of the records
+func (h *captureHandler) attrs() []map[string]any {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	var logs []map[string]any
+	for _, r := range h.records {
+		attrs := map[string]any{"msg": r.Message, "level": r.Level}
+		r.Attrs(func(a slog.Attr) bool {
+			attrs[a.Key] = a.Value.Any()
+			return true
+		})
+		logs = append(logs, attrs)
+	}
+	return logs
+}
+
+type panicEvent struct{}
+
+func (e *panicEvent) Dispatch(topic string, data ...string) {
+	panic("boom")
+}
+
+func TestWithLogger(t *testing.T) {
+	o := New[string]()
+	n := 0
+	h := &captureHandler{}
+
+	// nothing is logged by default
+	o.On("foo", &N{&n, ""})
+	o.WithLogger(slog.New(h)).OnPanic(func(topic string, e Event[string], v any) {})
+	e := &N{&n, ""}
+	o.On("bar", e, &N{&n, ""}).Off("bar", e)
+	o.OnE("baz", &failEvent{err: errors.New("failed")}).On("qux", &panicEvent{})
+	o.Trigger("baz").Trigger("qux")
+
+	logs := h.attrs()
+	expected := []map[string]any{
+		{"msg": "eventbus: subscribe", "level": slog.LevelDebug, "topic": "bar", "count": int64(2)},
+		{"msg": "eventbus: unsubscribe", "level": slog.LevelDebug, "topic": "bar", "count": int64(1)},
+		{"msg": "eventbus: subscribe", "level": slog.LevelDebug, "topic": "baz", "count": int64(1)},
+		{"msg": "eventbus: subscribe", "level": slog.LevelDebug, "topic": "qux", "count": int64(1)},
+		{"msg": "eventbus: dispatch error", "level": slog.LevelError, "topic": "baz"},
+		{"msg": "eventbus: panic", "level": slog.LevelError, "topic": "qux", "panic": "boom"},
+	}
+	if len(logs) != len(expected) {
+		t.Fatalf("The logs are %v instead of being %v", logs, expected)
+	}
+	for i, attrs := range expected {
+		for k, v := range attrs {
+			if logs[i][k] != v {
+				t.Errorf("The %s of the log %d is %v instead of being %v", k, i, logs[i][k], v)
+			}
+		}
+	}
+	if err, ok := logs[4]["error"].(error); !ok || err.Error() != "failed" {
+		t.Errorf("The error is %v instead of being %s", logs[4]["error"], "failed")
+	}
+}
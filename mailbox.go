@@ -0,0 +1,156 @@
+package eventbus
+
+import (
+	"context"
+	"sync"
+)
+
+// mailboxCall struct, a message waiting in the mailbox
+type mailboxCall[T any] struct {
+	m    *message[T]
+	data []T
+	done func()
+}
+
+// mailbox struct, the wrapper of OnAsync, OnSerial and OnConflate. It calls the wrapped event on a dedicated
+// goroutine fed by a queue of size messages, Dispatch waits while the queue is full and size <= 0 is unbounded.
+// With latest, the queue holds the latest message only and the waiting one is dropped when a new one arrives
+type mailbox[T any] struct {
+	bus     *Bus[T]
+	e       Event[T]
+	size    int
+	latest  bool
+	mu      sync.Mutex
+	space   sync.Cond
+	queue   []mailboxCall[T]
+	wake    chan struct{}
+	stopped chan struct{}
+	once    sync.Once
+}
+
+func newMailbox[T any](bus *Bus[T], e Event[T], size int, latest bool) *mailbox[T] {
+	mb := &mailbox[T]{
+		bus:     bus,
+		e:       e,
+		size:    size,
+		latest:  latest,
+		wake:    make(chan struct{}, 1),
+		stopped: make(chan struct{}),
+	}
+	mb.space.L = &mb.mu
+	go mb.loop()
+	return mb
+}
+
+func (mb *mailbox[T]) loop() {
+	for {
+		select {
+		case <-mb.wake:
+			for c, ok := mb.pop(); ok; c, ok = mb.pop() {
+				mb.dispatch(c)
+			}
+		case <-mb.stopped:
+			return
+		}
+	}
+}
+
+// pop returns the oldest message of the queue, ok is false if it is empty
+func (mb *mailbox[T]) pop() (c mailboxCall[T], ok bool) {
+	mb.mu.Lock()
+	defer mb.mu.Unlock()
+	if len(mb.queue) == 0 {
+		return c, false
+	}
+	c = mb.queue[0]
+	mb.queue = mb.queue[1:]
+	mb.space.Signal()
+	return c, true
+}
+
+// dispatch calls the wrapped event, the message is dropped if it is stopped meanwhile
+func (mb *mailbox[T]) dispatch(c mailboxCall[T]) {
+	defer c.done()
+	if mb.isStopped() {
+		mb.bus.drop(c.m.topic, DropStopped, c.data)
+		return
+	}
+	defer mb.bus.recoverPanic(c.m.topic, mb.e)
+	mb.bus.deliver(mb.e, c.m, c.data)
+}
+
+func (mb *mailbox[T]) isStopped() bool {
+	select {
+	case <-mb.stopped:
+		return true
+	default:
+		return false
+	}
+}
+
+func (mb *mailbox[T]) Dispatch(topic string, data ...T) {
+	mb.relay(newMessage(topic, data), data)
+}
+
+// relay puts the message to the queue, it waits only while the queue is full
+func (mb *mailbox[T]) relay(m *message[T], data []T) {
+	mb.mu.Lock()
+	for !mb.latest && mb.size > 0 && len(mb.queue) >= mb.size && !mb.isStopped() {
+		mb.space.Wait()
+	}
+	if mb.isStopped() {
+		mb.mu.Unlock()
+		mb.bus.drop(m.topic, DropStopped, data)
+		return
+	}
+	var dropped []mailboxCall[T]
+	if mb.latest {
+		dropped, mb.queue = mb.queue, nil
+	}
+	mb.queue = append(mb.queue, mailboxCall[T]{m, data, mb.bus.pending.add()})
+	mb.mu.Unlock()
+
+	for _, c := range dropped {
+		mb.bus.drop(c.m.topic, DropConflate, c.data)
+		c.done()
+	}
+	select {
+	case mb.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (mb *mailbox[T]) unwrap() Event[T] {
+	return mb.e
+}
+
+func (mb *mailbox[T]) clone(b *Bus[T]) Event[T] {
+	return newMailbox(b, mb.e, mb.size, mb.latest)
+}
+
+func (mb *mailbox[T]) OnStopCtx(ctx context.Context, topic string) {
+	mb.halt()
+	callStop(ctx, mb.e, topic)
+}
+
+// halt stops the goroutine and drops the messages left in the queue, the waiting senders drop theirs
+func (mb *mailbox[T]) halt() {
+	mb.once.Do(func() {
+		mb.mu.Lock()
+		close(mb.stopped)
+		queue := mb.queue
+		mb.queue = nil
+		mb.space.Broadcast()
+		mb.mu.Unlock()
+		for _, c := range queue {
+			mb.bus.drop(c.m.topic, DropStopped, c.data)
+			c.done()
+		}
+	})
+}
+
+// onMailbox registers topic event wrapped by a mailbox
+func (b *Bus[T]) onMailbox(topic string, e Event[T], size int, latest bool) *Bus[T] {
+	b.insertEvents(topic, []*event[T]{newEvent[T](newMailbox(b, e, size, latest), topic, false)})
+	return b
+}
@@ -0,0 +1,53 @@
+package eventbus
+
+import (
+	"testing"
+)
+
+func TestMailboxHandler(t *testing.T) {
+	cases := []struct {
+		name string
+		on   func(o *Bus[string], e Event[string]) *Bus[string]
+	}{
+		{"OnAsync", func(o *Bus[string], e Event[string]) *Bus[string] { return o.OnAsync("foo", 10, e) }},
+		{"OnSerial", func(o *Bus[string], e Event[string]) *Bus[string] { return o.OnSerial("foo", e) }},
+		{"OnConflate", func(o *Bus[string], e Event[string]) *Bus[string] { return o.OnConflate("foo", e) }},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			e := &N{new(int), ""}
+
+			// the registered event is passed back instead of the internal wrapper
+			o := c.on(New[string](), e)
+			o.EachEvent("foo", func(v Event[string], isOnce bool) {
+				if v != e {
+					t.Errorf("The event is %T instead of being the registered event", v)
+				}
+			})
+			if v, ok := o.OffIndex("foo", 0); !ok || v != e {
+				t.Errorf("The event is %T instead of being the registered event", v)
+			}
+			if c.on(o, e); o.OffFunc("foo", func(v Event[string]) bool { return v == e }) != 1 {
+				t.Error("The event is not found by OffFunc")
+			}
+
+			var stopped []Event[string]
+			o = New[string]().OnBatchStop(func(topic string, handlers []Event[string]) {
+				stopped = append(stopped, handlers...)
+			})
+			c.on(o, e).CleanSync()
+			if len(stopped) != 1 || stopped[0] != e {
+				t.Errorf("The stopped events are %v instead of being the registered event", stopped)
+			}
+
+			// Drain stops the goroutine of the wrapper
+			mb := c.on(o, e).Get("foo").snapshot()[0].Event.(*mailbox[string])
+			if drained := o.Drain("foo"); len(drained) != 1 || drained[0] != e {
+				t.Errorf("The drained events are %v instead of being the registered event", drained)
+			}
+			if !mb.isStopped() {
+				t.Error("The goroutine of the drained event is not stopped")
+			}
+		})
+	}
+}
@@ -0,0 +1,27 @@
+package eventbus
+
+import (
+	"context"
+)
+
+// mapEvent struct, it decodes the data before dispatching them to the wrapped event
+type mapEvent[T, U any] struct {
+	decode func([]T) []U
+	e      Event[U]
+}
+
+func (m *mapEvent[T, U]) Dispatch(topic string, data ...T) {
+	m.e.Dispatch(topic, m.decode(data)...)
+}
+
+func (m *mapEvent[T, U]) OnStopCtx(ctx context.Context, topic string) {
+	callStop(ctx, m.e, topic)
+}
+
+// OnMap - register an event of another message type to the topic, the data are decoded before
+// being dispatched to it. The returned adapter is the registered event, use it to remove by Off
+func OnMap[T, U any](b *Bus[T], topic string, decode func([]T) []U, e Event[U]) Event[T] {
+	m := &mapEvent[T, U]{decode, e}
+	b.On(topic, m)
+	return m
+}
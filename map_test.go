@@ -0,0 +1,54 @@
+package eventbus
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+type order struct {
+	ID   int    `json:"id"`
+	Paid bool   `json:"paid"`
+	Name string `json:"name"`
+}
+
+type orderEvent struct {
+	orders []order
+}
+
+func (e *orderEvent) Dispatch(topic string, data ...order) {
+	e.orders = append(e.orders, data...)
+}
+
+func decodeOrders(data [][]byte) []order {
+	orders := make([]order, 0, len(data))
+	for _, b := range data {
+		var o order
+		if err := json.Unmarshal(b, &o); err == nil {
+			orders = append(orders, o)
+		}
+	}
+	return orders
+}
+
+func TestOnMap(t *testing.T) {
+	o := New[[]byte]()
+
+	onOrder := &orderEvent{}
+	adapter := OnMap(o, "order", decodeOrders, onOrder)
+
+	o.Trigger("order", []byte(`{"id":1,"paid":true,"name":"foo"}`), []byte(`{"id":2,"name":"bar"}`))
+	if len(onOrder.orders) != 2 {
+		t.Fatalf("The order count is %d instead of being %d", len(onOrder.orders), 2)
+	}
+	if onOrder.orders[0] != (order{1, true, "foo"}) || onOrder.orders[1] != (order{2, false, "bar"}) {
+		t.Errorf("The orders are %v instead of being %v", onOrder.orders, []order{{1, true, "foo"}, {2, false, "bar"}})
+	}
+
+	o.Off("order", adapter).Trigger("order", []byte(`{"id":3}`))
+	if len(onOrder.orders) != 2 {
+		t.Errorf("The order count is %d instead of being %d", len(onOrder.orders), 2)
+	}
+	if c := o.EventCount("order"); c != 0 {
+		t.Errorf("The event count is %d instead of being %d", c, 0)
+	}
+}
@@ -0,0 +1,37 @@
+package eventbus
+
+// The topics of the meta events
+const (
+	// MetaSub - the events are subscribed to the topic
+	MetaSub = "$sub"
+	// MetaUnsub - the events are unsubscribed from the topic
+	MetaUnsub = "$unsub"
+	// MetaTrigger - a message is dispatched to the events of the topic
+	MetaTrigger = "$trigger"
+)
+
+// MetaEvent struct, the lifecycle change of a topic published by the meta bus
+type MetaEvent struct {
+	// Topic is the affected topic
+	Topic string
+	// Count is the number of the subscribed, unsubscribed or called events
+	Count int
+}
+
+// Meta - return the bus which publishes the meta events of b, it is created on the first call.
+// The meta events are dispatched synchronously by the goroutine which changes b
+func (b *Bus[T]) Meta() *Bus[MetaEvent] {
+	if m := b.meta.Load(); m != nil {
+		return m
+	}
+	b.meta.CompareAndSwap(nil, New[MetaEvent]())
+	return b.meta.Load()
+}
+
+// publishMeta publishes the meta event if the meta bus is created,
+// the changes without any subscribed or unsubscribed event are skipped
+func (b *Bus[T]) publishMeta(kind, topic string, count int) {
+	if m := b.meta.Load(); m != nil && (count > 0 || kind == MetaTrigger) {
+		m.Emit(kind, MetaEvent{Topic: topic, Count: count})
+	}
+}
@@ -0,0 +1,55 @@
+package eventbus
+
+import (
+	"testing"
+)
+
+type metaEvent struct {
+	logs []string
+	data []MetaEvent
+}
+
+func (e *metaEvent) Dispatch(topic string, data ...MetaEvent) {
+	e.logs = append(e.logs, topic)
+	e.data = append(e.data, data...)
+}
+
+func TestMeta(t *testing.T) {
+	o := New[string]()
+	n := 0
+
+	// the changes before the meta bus is created are not published
+	o.On("foo", &N{&n, ""})
+
+	meta := &metaEvent{}
+	if o.Meta() != o.Meta() {
+		t.Error("The meta bus should be created once")
+	}
+	o.Meta().On(MetaSub, meta).On(MetaUnsub, meta).On(MetaTrigger, meta)
+
+	e := &N{&n, ""}
+	o.On("bar", e, &N{&n, ""}).Trigger("bar").Trigger("baz").Off("bar", e)
+	o.CleanSync()
+
+	expected := []MetaEvent{
+		{"bar", 2},
+		{"bar", 2},
+		{"baz", 0},
+		{"bar", 1},
+		{"foo", 1},
+		{"bar", 1},
+	}
+	logs := []string{MetaSub, MetaTrigger, MetaTrigger, MetaUnsub, MetaUnsub, MetaUnsub}
+	if len(meta.data) != len(expected) {
+		t.Fatalf("The meta events are %v instead of being %v", meta.data, expected)
+	}
+	// the topics are cleaned in a random order
+	if meta.data[4].Topic == "bar" {
+		meta.data[4], meta.data[5] = meta.data[5], meta.data[4]
+	}
+	for i, v := range expected {
+		if meta.logs[i] != logs[i] || meta.data[i] != v {
+			t.Errorf("The meta event %d is %s %v instead of being %s %v", i, meta.logs[i], meta.data[i], logs[i], v)
+		}
+	}
+}
@@ -0,0 +1,151 @@
+package eventbus
+
+import (
+	"fmt"
+	"strconv"
+	"sync"
+	"testing"
+)
+
+// orderLog struct, the shared log of the invocations of indexEvent
+type orderLog struct {
+	mu      sync.Mutex
+	entries []string
+}
+
+func (l *orderLog) add(entry string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.entries = append(l.entries, entry)
+}
+
+func (l *orderLog) reset() []string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	entries := l.entries
+	l.entries = nil
+	return entries
+}
+
+// indexEvent struct, it records its index and the message into the log
+type indexEvent struct {
+	index int
+	log   *orderLog
+}
+
+func (e *indexEvent) Dispatch(topic string, data ...string) {
+	e.log.add(fmt.Sprintf("%s:%d", data[0], e.index))
+}
+
+// expectedOrder returns the log of the messages dispatched one by one to the handlers in the index order
+func expectedOrder(messages []string, indexes []int) []string {
+	var entries []string
+	for _, m := range messages {
+		for _, i := range indexes {
+			entries = append(entries, fmt.Sprintf("%s:%d", m, i))
+		}
+	}
+	return entries
+}
+
+func checkOrder(t *testing.T, name string, got, want []string) {
+	t.Helper()
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("The order of %s is %v instead of being %v", name, got, want)
+	}
+}
+
+func TestOrder(t *testing.T) {
+	const handlers = 10
+	log := &orderLog{}
+	o := New[string]().WithTopicCapacity("foo", handlers)
+
+	events := make([]*indexEvent, 0, handlers)
+	indexes := make([]int, 0, handlers)
+	for i := 0; i < handlers; i++ {
+		e := &indexEvent{i, log}
+		events = append(events, e)
+		indexes = append(indexes, i)
+		o.On("foo", e)
+	}
+	messages := make([]string, 0, 50)
+	for i := 0; i < 50; i++ {
+		messages = append(messages, strconv.Itoa(i))
+	}
+
+	for _, m := range messages {
+		o.Trigger("foo", m)
+	}
+	checkOrder(t, "Trigger", log.reset(), expectedOrder(messages, indexes))
+
+	for _, m := range messages {
+		o.Broadcast(m)
+	}
+	checkOrder(t, "Broadcast", log.reset(), expectedOrder(messages, indexes))
+
+	// the messages of a key are dispatched one by one in order
+	for _, m := range messages {
+		o.TriggerKey("key", "foo", m)
+	}
+	o.Flush()
+	checkOrder(t, "TriggerKey", log.reset(), expectedOrder(messages, indexes))
+
+	// the buffered messages are dispatched in arrival order
+	o.PauseAll()
+	for _, m := range messages {
+		o.Trigger("foo", m)
+	}
+	o.ResumeAll()
+	checkOrder(t, "ResumeAll", log.reset(), expectedOrder(messages, indexes))
+
+	// the ALL events are called after the topic events, before them with AsteriskFirst
+	onAll := &indexEvent{handlers, log}
+	o.On(ALL, onAll).Trigger("foo", "a")
+	checkOrder(t, "ALL", log.reset(), expectedOrder([]string{"a"}, append(indexes, handlers)))
+	o.AsteriskFirst().Trigger("foo", "b")
+	checkOrder(t, "AsteriskFirst", log.reset(), expectedOrder([]string{"b"}, append([]int{handlers}, indexes...)))
+	o.Off(ALL, onAll)
+
+	// the removals, the suspensions and the changes keep the order of the other events
+	o.Off("foo", events[3])
+	o.SetOnce("foo", events[5], true)
+	o.Suspend("foo", events[7])
+	o.On("foo", events[3])
+	o.Trigger("foo", "c")
+	checkOrder(t, "Off and On", log.reset(), expectedOrder([]string{"c"}, []int{0, 1, 2, 4, 5, 6, 8, 9, 3}))
+	o.Unsuspend("foo", events[7]).Trigger("foo", "d")
+	checkOrder(t, "Unsuspend", log.reset(), expectedOrder([]string{"d"}, []int{0, 1, 2, 4, 6, 7, 8, 9, 3}))
+}
+
+func TestOrderAsync(t *testing.T) {
+	log := &orderLog{}
+	o := New[string]()
+
+	var indexes []int
+	for i := 0; i < 5; i++ {
+		o.On("foo", &indexEvent{i, log})
+		indexes = append(indexes, i)
+	}
+
+	// each async message is dispatched to the handlers in order
+	var messages []string
+	for i := 0; i < 20; i++ {
+		messages = append(messages, strconv.Itoa(i))
+		<-o.TriggerAsync("foo", strconv.Itoa(i))
+	}
+	checkOrder(t, "TriggerAsync", log.reset(), expectedOrder(messages, indexes))
+
+	// the mailbox of OnAsync and OnSerial keeps the arrival order
+	for _, on := range []func(e Event[string]){
+		func(e Event[string]) { o.OnAsync("bar", 100, e) },
+		func(e Event[string]) { o.OnSerial("bar", e) },
+	} {
+		o.CleanSync()
+		on(&indexEvent{0, log})
+		for _, m := range messages {
+			o.Trigger("bar", m)
+		}
+		o.Flush()
+		checkOrder(t, "mailbox", log.reset(), expectedOrder(messages, []int{0}))
+	}
+}
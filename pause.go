@@ -0,0 +1,106 @@
+package eventbus
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// DefaultPauseSize - the default number of the messages buffered while the bus is paused
+const DefaultPauseSize = 1024
+
+// PausePolicy type, what happens to the messages triggered while the bus is paused
+type PausePolicy int
+
+const (
+	// PauseBuffer - buffer the messages and dispatch them on resume, the messages over the size are dropped
+	PauseBuffer PausePolicy = iota
+	// PauseDrop - drop the messages
+	PauseDrop
+)
+
+// pause struct, it holds back the dispatches while the bus is paused
+type pause struct {
+	mu       sync.Mutex
+	resumeMu sync.Mutex
+	on       atomic.Bool
+	policy   PausePolicy
+	size     int
+	queue    []func()
+}
+
+// hold returns whether the dispatch is held back, fn is buffered to be called on resume,
+// drop is called instead if fn isn't buffered
+func (p *pause) hold(fn func(), drop func()) bool {
+	if !p.on.Load() {
+		return false
+	}
+	p.mu.Lock()
+	if !p.on.Load() {
+		p.mu.Unlock()
+		return false
+	}
+	size := p.size
+	if size <= 0 {
+		size = DefaultPauseSize
+	}
+	buffered := p.policy == PauseBuffer && len(p.queue) < size
+	if buffered {
+		p.queue = append(p.queue, fn)
+	}
+	p.mu.Unlock()
+
+	if !buffered {
+		drop()
+	}
+	return true
+}
+
+// resume calls the buffered dispatches in arrival order, the bus stays paused until the buffer is empty
+// so that the messages triggered meanwhile are dispatched after them
+func (p *pause) resume() {
+	p.resumeMu.Lock()
+	defer p.resumeMu.Unlock()
+	for {
+		p.mu.Lock()
+		queue := p.queue
+		p.queue = nil
+		if len(queue) == 0 {
+			p.on.Store(false)
+			p.mu.Unlock()
+			return
+		}
+		p.mu.Unlock()
+		for _, fn := range queue {
+			fn()
+		}
+	}
+}
+
+// WithPausePolicy - set what happens to the messages triggered while the bus is paused, size limits the number
+// of the buffered messages, default is PauseBuffer with DefaultPauseSize
+func (b *Bus[T]) WithPausePolicy(policy PausePolicy, size int) *Bus[T] {
+	b.pause.mu.Lock()
+	defer b.pause.mu.Unlock()
+	b.pause.policy = policy
+	b.pause.size = size
+	return b
+}
+
+// PauseAll - stop dispatching the messages of every topic until ResumeAll, the messages triggered meanwhile
+// are buffered or dropped by the pause policy
+func (b *Bus[T]) PauseAll() *Bus[T] {
+	b.pause.on.Store(true)
+	return b
+}
+
+// ResumeAll - dispatch the buffered messages in arrival order across the topics and resume dispatching,
+// it returns after the buffered messages are dispatched
+func (b *Bus[T]) ResumeAll() *Bus[T] {
+	b.pause.resume()
+	return b
+}
+
+// IsPaused - return whether the bus is paused by PauseAll
+func (b *Bus[T]) IsPaused() bool {
+	return b.pause.on.Load()
+}
@@ -0,0 +1,80 @@
+package eventbus
+
+import (
+	"context"
+	"fmt"
+	"testing"
+)
+
+func TestPauseAll(t *testing.T) {
+	o := New[string]()
+	e := &logEvent{}
+	o.On("foo", e).On("bar", e)
+
+	o.PauseAll()
+	if !o.IsPaused() {
+		t.Error("The bus should be paused")
+	}
+	o.Trigger("foo", "1").Trigger("bar", "2").Broadcast("3").Trigger("foo", "4")
+	if logs := e.Logs(); len(logs) != 0 {
+		t.Errorf("The messages are %v while the bus is paused", logs)
+	}
+
+	o.ResumeAll()
+	if o.IsPaused() {
+		t.Error("The bus should be resumed")
+	}
+	// the broadcast reaches both topics between the triggers
+	if logs := fmt.Sprint(e.Logs()); logs != "[1 2 3 3 4]" {
+		t.Errorf("The messages are %s instead of being %s", logs, "[1 2 3 3 4]")
+	}
+
+	o.Trigger("foo", "5")
+	if logs := fmt.Sprint(e.Logs()); logs != "[1 2 3 3 4 5]" {
+		t.Errorf("The messages are %s instead of being %s", logs, "[1 2 3 3 4 5]")
+	}
+}
+
+func TestPausePolicy(t *testing.T) {
+	o := New[string]().WithPausePolicy(PauseBuffer, 2)
+	n := 0
+	o.On("foo", &N{&n, ""})
+
+	o.PauseAll().Trigger("foo", "1").Trigger("foo", "2").Trigger("foo", "3").ResumeAll()
+	if n != 2 {
+		t.Errorf("The counter is %d instead of being %d", n, 2)
+	}
+
+	o.WithPausePolicy(PauseDrop, 0).PauseAll().Trigger("foo", "4").ResumeAll()
+	if n != 2 {
+		t.Errorf("The counter is %d instead of being %d", n, 2)
+	}
+}
+
+func TestPauseSyncCalls(t *testing.T) {
+	o := New[string]()
+	n := 0
+	onFoo := &N{&n, ""}
+	var drops []string
+	o.On("foo", onFoo, &lenEvent{1}).OnDrop(func(topic string, reason DropReason, data []string) {
+		drops = append(drops, reason.String())
+	})
+
+	// the calls returning the result of the handlers are dropped while paused and after Shutdown
+	o.PauseAll()
+	if o.TriggerTo("foo", onFoo, "1") || Request[string, int](o, "foo", "1") != nil {
+		t.Error("The handlers should not be called while paused")
+	}
+	o.ResumeAll()
+	if n != 0 {
+		t.Errorf("The counter is %d instead of being %d", n, 0)
+	}
+
+	o.Shutdown(context.Background())
+	if o.TriggerTo("foo", onFoo, "1") || Request[string, int](o, "foo", "1") != nil || n != 0 {
+		t.Errorf("The handlers should not be called after Shutdown, the counter is %d", n)
+	}
+	if fmt.Sprint(drops) != "[paused paused closed closed]" {
+		t.Errorf("The drops are %v instead of being %v", drops, "[paused paused closed closed]")
+	}
+}
@@ -0,0 +1,74 @@
+package eventbus
+
+import (
+	"cmp"
+	"math"
+	"slices"
+	"time"
+)
+
+// OnPriority - register topic events with the priority, the events of a higher priority are called first and the
+// events of the same priority are called in registration order. On registers the events with priority 0.
+// When asterisk is allowed, the events of the topic, its ancestors and ALL are called in a single priority order,
+// so an ALL event may run between the topic events
+func (b *Bus[T]) OnPriority(topic string, priority int, e ...Event[T]) *Bus[T] {
+	events := make([]*event[T], 0, len(e))
+	for _, v := range e {
+		ev := newEvent(v, topic, false)
+		ev.priority = priority
+		events = append(events, ev)
+	}
+	b.insertEvents(topic, events)
+	return b
+}
+
+// appendable returns whether es can be appended to events without breaking the priority order
+func appendable[T any](events, es []*event[T]) bool {
+	prev := math.MaxInt
+	if len(events) > 0 {
+		prev = events[len(events)-1].priority
+	}
+	for _, e := range es {
+		if e.priority > prev {
+			return false
+		}
+		prev = e.priority
+	}
+	return true
+}
+
+// prioritized returns whether any event of the batches has a priority
+func prioritized[T any](batches []batch[T]) bool {
+	for i := range batches {
+		for _, e := range batches[i].calls {
+			if e.priority != 0 {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// callMerged calls the events of the batches in a single priority order, the events of the same priority keep
+// the order of their batches. The events are called one by one even if their topic is parallel, and the
+// duration is recorded to the topic of m
+func (b *Bus[T]) callMerged(batches []batch[T], m *message[T]) {
+	var calls []*event[T]
+	for i := range batches {
+		calls = append(calls, batches[i].calls...)
+	}
+	slices.SortStableFunc(calls, func(x, y *event[T]) int {
+		return cmp.Compare(y.priority, x.priority)
+	})
+
+	var start time.Time
+	if b.timing && len(calls) > 0 {
+		start = time.Now()
+	}
+	for _, e := range calls {
+		b.call(e, m)
+	}
+	if !start.IsZero() {
+		b.recordLatency(m.topic, time.Since(start))
+	}
+}
@@ -0,0 +1,43 @@
+package eventbus
+
+import (
+	"testing"
+)
+
+func TestOnPriority(t *testing.T) {
+	log := &orderLog{}
+	o := New[string]()
+
+	o.On("foo", &indexEvent{0, log})
+	o.OnPriority("foo", 10, &indexEvent{1, log}, &indexEvent{2, log})
+	o.OnPriority("foo", -1, &indexEvent{3, log})
+	o.On("foo", &indexEvent{4, log})
+	o.OnPriority("foo", 10, &indexEvent{5, log})
+
+	o.Trigger("foo", "a")
+	checkOrder(t, "the topic", log.reset(), expectedOrder([]string{"a"}, []int{1, 2, 5, 0, 4, 3}))
+}
+
+func TestOnPriorityAsterisk(t *testing.T) {
+	log := &orderLog{}
+	o := New[string]()
+
+	o.OnPriority("foo", 30, &indexEvent{0, log})
+	o.OnPriority(ALL, 20, &indexEvent{1, log})
+	o.OnPriority("foo", 10, &indexEvent{2, log})
+	o.OnPriority(ALL, 10, &indexEvent{3, log})
+	o.On(ALL, &indexEvent{4, log})
+	o.OnPriority("foo", -10, &indexEvent{5, log})
+
+	// the topic events go first among the events of the same priority
+	o.Trigger("foo", "a")
+	checkOrder(t, "the merged events", log.reset(), expectedOrder([]string{"a"}, []int{0, 1, 2, 3, 4, 5}))
+
+	// the ALL events go first among the events of the same priority
+	o.AsteriskFirst().Trigger("foo", "b")
+	checkOrder(t, "the merged events", log.reset(), expectedOrder([]string{"b"}, []int{0, 1, 3, 2, 4, 5}))
+
+	// the ALL events are not called when asterisk is not allowed
+	o.AllowAsterisk(false).Trigger("foo", "c")
+	checkOrder(t, "the topic events", log.reset(), expectedOrder([]string{"c"}, []int{0, 2, 5}))
+}
@@ -0,0 +1,36 @@
+package eventbus
+
+// RespEvent interface, Respond is called by Request and its result is gathered
+type RespEvent[T, R any] interface {
+	Event[T]
+	Respond(topic string, data ...T) R
+}
+
+// Request - call Respond of the topic events which implement RespEvent and return the results
+// in registration order. The other events are skipped and the once events are not removed.
+// The result of a panicking Respond is dropped when the panic handler is set. msg is dropped after Shutdown
+// or while the bus is paused, since it is never buffered
+func Request[T, R any](b *Bus[T], topic string, msg ...T) []R {
+	if !b.admit(topic, msg) {
+		return nil
+	}
+	epoch := b.inflight.enter()
+	defer b.inflight.exit(epoch)
+	var results []R
+	for _, e := range b.Get(topic).snapshot() {
+		r, ok := e.handler().(RespEvent[T, R])
+		if !ok {
+			continue
+		}
+		if v, ok := respond(b, topic, r, msg); ok {
+			results = append(results, v)
+		}
+	}
+	return results
+}
+
+// respond calls Respond of e, ok is false if it panics
+func respond[T, R any](b *Bus[T], topic string, e RespEvent[T, R], data []T) (v R, ok bool) {
+	defer b.recoverPanic(topic, e)
+	return e.Respond(topic, data...), true
+}
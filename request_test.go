@@ -0,0 +1,57 @@
+package eventbus
+
+import (
+	"testing"
+)
+
+type lenEvent struct {
+	mul int
+}
+
+func (e *lenEvent) Dispatch(topic string, data ...string) {}
+
+func (e *lenEvent) Respond(topic string, data ...string) int {
+	if e.mul < 0 {
+		panic("negative")
+	}
+	n := 0
+	for _, s := range data {
+		n += len(s)
+	}
+	return n * e.mul
+}
+
+func TestRequest(t *testing.T) {
+	o := New[string]()
+	n := 0
+
+	if r := Request[string, int](o, "foo", "a"); len(r) != 0 {
+		t.Errorf("The results are %v instead of being empty", r)
+	}
+
+	o.On("foo", &lenEvent{1}, &N{&n, ""}, &lenEvent{2}).Once("foo", &lenEvent{3})
+	r := Request[string, int](o, "foo", "ab", "c")
+	if len(r) != 3 || r[0] != 3 || r[1] != 6 || r[2] != 9 {
+		t.Errorf("The results are %v instead of being %v", r, []int{3, 6, 9})
+	}
+	if n != 0 {
+		t.Errorf("The counter is %d instead of being %d", n, 0)
+	}
+	if c := o.EventCount("foo"); c != 4 {
+		t.Errorf("The event count is %d instead of being %d", c, 4)
+	}
+
+	// the results of other types are not gathered
+	if r := Request[string, string](o, "foo", "a"); len(r) != 0 {
+		t.Errorf("The results are %v instead of being empty", r)
+	}
+
+	// the panicking event is dropped
+	var panics int
+	o.OnPanic(func(topic string, e Event[string], v any) {
+		panics++
+	}).On("bar", &lenEvent{-1}, &lenEvent{1})
+	if r := Request[string, int](o, "bar", "a"); len(r) != 1 || r[0] != 1 || panics != 1 {
+		t.Errorf("The results are %v with %d panics instead of being %v with %d panics", r, panics, []int{1}, 1)
+	}
+}
@@ -0,0 +1,106 @@
+package eventbus
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+)
+
+// RetryPolicy struct, the retries of the failed ErrEvent
+type RetryPolicy struct {
+	// MaxAttempts is the number of the calls including the first one
+	MaxAttempts int
+	// Backoff is the delay before the first retry, it is doubled before each next retry
+	Backoff time.Duration
+}
+
+// retryEvent struct, it adapts ErrEvent to Event and retries the failed calls on another goroutine
+type retryEvent[T any] struct {
+	bus     *Bus[T]
+	policy  RetryPolicy
+	e       ErrEvent[T]
+	stopped chan struct{}
+	once    sync.Once
+}
+
+func (r *retryEvent[T]) Dispatch(topic string, data ...T) {
+	err := r.e.Dispatch(topic, data...)
+	if err == nil {
+		return
+	}
+	if r.policy.MaxAttempts <= 1 {
+		r.bus.reportError(topic, r.e, err)
+		return
+	}
+	// the data is copied since the retries outlive the dispatch
+	data = slices.Clone(data)
+	done := r.bus.pending.add()
+	go func() {
+		defer done()
+		defer r.bus.recoverPanic(topic, ErrHandler[T]{r.e})
+		r.retry(topic, data, err)
+	}()
+}
+
+// retry calls the event until it succeeds or the attempts are exhausted, the last error is reported.
+// The retries are given up when the event is stopped, the error of the last call is reported then
+func (r *retryEvent[T]) retry(topic string, data []T, err error) {
+	backoff := r.policy.Backoff
+	for attempt := 1; attempt < r.policy.MaxAttempts; attempt++ {
+		timer := time.NewTimer(backoff)
+		select {
+		case <-timer.C:
+		case <-r.stopped:
+			timer.Stop()
+			r.bus.reportError(topic, r.e, err)
+			return
+		}
+		backoff *= 2
+		if err = r.e.Dispatch(topic, data...); err == nil {
+			return
+		}
+	}
+	r.bus.reportError(topic, r.e, err)
+}
+
+func (r *retryEvent[T]) unwrap() Event[T] {
+	return ErrHandler[T]{r.e}
+}
+
+func (r *retryEvent[T]) clone(b *Bus[T]) Event[T] {
+	return &retryEvent[T]{bus: b, policy: r.policy, e: r.e, stopped: make(chan struct{})}
+}
+
+func (r *retryEvent[T]) OnStopCtx(ctx context.Context, topic string) {
+	r.halt()
+	callStop(ctx, r.e, topic)
+}
+
+// halt gives up the pending retries
+func (r *retryEvent[T]) halt() {
+	r.once.Do(func() {
+		close(r.stopped)
+	})
+}
+
+// haltRetries gives up the pending retries of all the events
+func (b *Bus[T]) haltRetries() {
+	b.topics.IterCb(func(_ string, t *Topic[T]) {
+		for _, e := range t.snapshot() {
+			if r, ok := e.Event.(*retryEvent[T]); ok {
+				r.halt()
+			}
+		}
+	})
+}
+
+// OnRetry - register topic ErrEvent which is retried on another goroutine when it fails, the error is passed to
+// the callback set by OnError after the attempts are exhausted. Use OffE to remove it and Flush to wait for the retries.
+// The pending retries are given up when the event is removed or the bus is shut down, the last error is reported then.
+// A retry runs on its own goroutine, so it may overlap a later dispatch of the same ErrEvent
+func (b *Bus[T]) OnRetry(topic string, policy RetryPolicy, e ErrEvent[T]) *Bus[T] {
+	ev := newEvent[T](&retryEvent[T]{bus: b, policy: policy, e: e, stopped: make(chan struct{})}, topic, false)
+	b.insertEvents(topic, []*event[T]{ev})
+	return b
+}
@@ -0,0 +1,112 @@
+package eventbus
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// flakyEvent struct, it fails until it is called the given times
+type flakyEvent struct {
+	fails int64
+	calls int64
+}
+
+func (e *flakyEvent) Dispatch(topic string, data ...string) error {
+	if atomic.AddInt64(&e.calls, 1) <= e.fails {
+		return errors.New("failed")
+	}
+	return nil
+}
+
+func TestOnRetry(t *testing.T) {
+	o := New[string]()
+	n := 0
+	var errs int64
+
+	o.OnError(func(topic string, e ErrEvent[string], err error) {
+		atomic.AddInt64(&errs, 1)
+	})
+	e := &flakyEvent{fails: 2}
+	o.OnRetry("foo", RetryPolicy{MaxAttempts: 3, Backoff: time.Millisecond}, e).On("foo", &N{&n, ""})
+	o.Trigger("foo")
+	// the other events are not blocked by the retries
+	if n != 1 {
+		t.Errorf("The counter is %d instead of being %d", n, 1)
+	}
+	o.Flush()
+	if c := atomic.LoadInt64(&e.calls); c != 3 {
+		t.Errorf("The calls are %d instead of being %d", c, 3)
+	}
+	if c := atomic.LoadInt64(&errs); c != 0 {
+		t.Errorf("The errors are %d instead of being %d", c, 0)
+	}
+
+	// the error is reported after the attempts are exhausted
+	failed := &flakyEvent{fails: 10}
+	o.OffE("foo", e).OnRetry("bar", RetryPolicy{MaxAttempts: 2}, failed).Trigger("bar").Flush()
+	if c := atomic.LoadInt64(&failed.calls); c != 2 {
+		t.Errorf("The calls are %d instead of being %d", c, 2)
+	}
+	if c := atomic.LoadInt64(&errs); c != 1 {
+		t.Errorf("The errors are %d instead of being %d", c, 1)
+	}
+	if o.EventCount("foo") != 1 {
+		t.Errorf("The event count is %d instead of being %d", o.EventCount("foo"), 1)
+	}
+}
+
+func TestOnRetryHandler(t *testing.T) {
+	o := New[string]()
+	e := &flakyEvent{}
+
+	// the ErrEvent is passed back as ErrHandler instead of the internal wrapper
+	o.OnRetry("foo", RetryPolicy{MaxAttempts: 3}, e)
+	o.EachEvent("foo", func(v Event[string], isOnce bool) {
+		if v != (ErrHandler[string]{e}) {
+			t.Errorf("The event is %T instead of being the ErrHandler of the ErrEvent", v)
+		}
+	})
+	if n := o.OffFunc("foo", func(v Event[string]) bool { return v == ErrHandler[string]{e} }); n != 1 {
+		t.Errorf("The counter is %d instead of being %d", n, 1)
+	}
+}
+
+func TestOnRetryStop(t *testing.T) {
+	o := New[string]()
+	var errs int64
+	o.OnError(func(topic string, e ErrEvent[string], err error) {
+		atomic.AddInt64(&errs, 1)
+	})
+
+	// the pending retries are given up when the event is removed
+	e := &flakyEvent{fails: 10}
+	o.OnRetry("foo", RetryPolicy{MaxAttempts: 3, Backoff: time.Hour}, e).Trigger("foo")
+	o.OffE("foo", e)
+	start := time.Now()
+	o.Flush()
+	if d := time.Since(start); d > time.Second {
+		t.Errorf("The flush takes %s", d)
+	}
+	if c := atomic.LoadInt64(&e.calls); c != 1 {
+		t.Errorf("The calls are %d instead of being %d", c, 1)
+	}
+	if c := atomic.LoadInt64(&errs); c != 1 {
+		t.Errorf("The errors are %d instead of being %d", c, 1)
+	}
+
+	// Shutdown doesn't wait for the backoff
+	o.OnRetry("foo", RetryPolicy{MaxAttempts: 3, Backoff: time.Hour}, e).Trigger("foo")
+	start = time.Now()
+	if err := o.Shutdown(context.Background()); err != nil {
+		t.Errorf("The error is %v instead of being nil", err)
+	}
+	if d := time.Since(start); d > time.Second {
+		t.Errorf("The shutdown takes %s", d)
+	}
+	if c := atomic.LoadInt64(&errs); c != 2 {
+		t.Errorf("The errors are %d instead of being %d", c, 2)
+	}
+}
@@ -0,0 +1,56 @@
+package eventbus
+
+import (
+	"sync"
+)
+
+// Router struct, the event which dispatches the messages to the funcs registered for their topics
+type Router[T any] struct {
+	mu       sync.RWMutex
+	handlers map[string]func(topic string, data ...T)
+	fallback func(topic string, data ...T)
+}
+
+// NewRouter - return a new Router, subscribe it to each topic or to ALL
+func NewRouter[T any]() *Router[T] {
+	return &Router[T]{handlers: make(map[string]func(topic string, data ...T))}
+}
+
+// Handle - set the func of the topic
+func (r *Router[T]) Handle(topic string, fn func(topic string, data ...T)) *Router[T] {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.handlers[topic] = fn
+	return r
+}
+
+// Default - set the func of the topics without any func
+func (r *Router[T]) Default(fn func(topic string, data ...T)) *Router[T] {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.fallback = fn
+	return r
+}
+
+// Topics - return the topics which have a func
+func (r *Router[T]) Topics() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	topics := make([]string, 0, len(r.handlers))
+	for topic := range r.handlers {
+		topics = append(topics, topic)
+	}
+	return topics
+}
+
+func (r *Router[T]) Dispatch(topic string, data ...T) {
+	r.mu.RLock()
+	fn, ok := r.handlers[topic]
+	if !ok {
+		fn = r.fallback
+	}
+	r.mu.RUnlock()
+	if fn != nil {
+		fn(topic, data...)
+	}
+}
@@ -0,0 +1,35 @@
+package eventbus
+
+import (
+	"testing"
+)
+
+func TestRouter(t *testing.T) {
+	o := New[string]()
+	var created, paid, others []string
+
+	r := NewRouter[string]().Handle("order.created", func(topic string, data ...string) {
+		created = append(created, data...)
+	}).Handle("order.paid", func(topic string, data ...string) {
+		paid = append(paid, data...)
+	})
+	for _, topic := range r.Topics() {
+		o.On(topic, r)
+	}
+	o.Trigger("order.created", "1").Trigger("order.paid", "2").Trigger("order.created", "3")
+	if len(created) != 2 || created[0] != "1" || created[1] != "3" {
+		t.Errorf("The created orders are %v instead of being %v", created, []string{"1", "3"})
+	}
+	if len(paid) != 1 || paid[0] != "2" {
+		t.Errorf("The paid orders are %v instead of being %v", paid, []string{"2"})
+	}
+
+	// the unmatched topics are passed to the default func
+	r.Default(func(topic string, data ...string) {
+		others = append(others, topic)
+	})
+	o.On("order.canceled", r).Trigger("order.canceled", "4")
+	if len(others) != 1 || others[0] != "order.canceled" {
+		t.Errorf("The unmatched topics are %v instead of being %v", others, []string{"order.canceled"})
+	}
+}
@@ -0,0 +1,10 @@
+package eventbus
+
+// OnSerial - register topic event whose Dispatch is never called concurrently, the calls are queued in an
+// unbounded mailbox and passed to a dedicated goroutine in the arrival order. Dispatch of the bus doesn't wait
+// for them, use Flush to wait for the queued messages. The event can trigger its own topic, the nested call
+// is queued like any other. Each registration has its own goroutine which exits after the event is removed,
+// the queued messages are dropped then
+func (b *Bus[T]) OnSerial(topic string, e Event[T]) *Bus[T] {
+	return b.onMailbox(topic, e, 0, false)
+}
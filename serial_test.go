@@ -0,0 +1,98 @@
+package eventbus
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// unsafeEvent struct, it is not goroutine safe and detects the concurrent calls
+type unsafeEvent struct {
+	running    int64
+	concurrent int64
+	n          int
+}
+
+func (e *unsafeEvent) Dispatch(topic string, data ...string) {
+	if atomic.AddInt64(&e.running, 1) > 1 {
+		atomic.AddInt64(&e.concurrent, 1)
+	}
+	e.n++
+	time.Sleep(time.Microsecond)
+	atomic.AddInt64(&e.running, -1)
+}
+
+// reentrantEvent struct, it triggers its own topic again on the first message
+type reentrantEvent struct {
+	bus  *Bus[string]
+	seen []string
+}
+
+func (e *reentrantEvent) Dispatch(topic string, data ...string) {
+	e.seen = append(e.seen, data...)
+	if data[0] == "1" {
+		e.bus.Trigger(topic, "2")
+		e.seen = append(e.seen, "1 done")
+	}
+}
+
+func TestOnSerial(t *testing.T) {
+	o := New[string]()
+	var calls int64
+	var wg sync.WaitGroup
+
+	e := &unsafeEvent{}
+	o.OnSerial("foo", e).On("foo", &benchmarkEvent{&calls})
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 10; j++ {
+				o.Trigger("foo")
+			}
+		}()
+	}
+	wg.Wait()
+	o.Flush()
+
+	if c := atomic.LoadInt64(&e.concurrent); c != 0 {
+		t.Errorf("The event is called concurrently %d times", c)
+	}
+	if e.n != 100 || atomic.LoadInt64(&calls) != 100 {
+		t.Errorf("The counters are %d and %d instead of being %d", e.n, calls, 100)
+	}
+
+	// the panic is passed to the panic handler of the bus with the registered event
+	var (
+		panics   []any
+		handlers []Event[string]
+	)
+	p := &panicEvent{}
+	o.OnPanic(func(topic string, e Event[string], v any) {
+		panics = append(panics, v)
+		handlers = append(handlers, e)
+	}).OnSerial("bar", p).Trigger("bar")
+	o.Flush()
+	if len(panics) != 1 || panics[0] != "boom" || handlers[0] != p {
+		t.Errorf("The panics are %v of %v instead of being %v of the registered event", panics, handlers, []any{"boom"})
+	}
+
+	// the event triggering its own topic doesn't wait for itself
+	r := &reentrantEvent{bus: o}
+	o.OnSerial("baz", r).Trigger("baz", "1")
+	// the nested message is queued after the first Flush starts, the second one waits for it
+	o.Flush()
+	o.Flush()
+	if fmt.Sprint(r.seen) != "[1 1 done 2]" {
+		t.Errorf("The calls are %v instead of being [1 1 done 2]", r.seen)
+	}
+
+	// the removed event is stopped
+	o.Off("foo", e)
+	o.Trigger("foo")
+	if e.n != 100 || o.Has("foo", e) {
+		t.Errorf("The counter is %d instead of being %d", e.n, 100)
+	}
+}
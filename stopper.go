@@ -0,0 +1,40 @@
+package eventbus
+
+import "sync"
+
+// stopper struct, it calls OnStop of the removed events on a single goroutine in the removal order,
+// so that a fast subscribe and unsubscribe loop doesn't start a goroutine per removal.
+// The goroutine is started on demand and exits once the queue is empty
+type stopper struct {
+	mu      sync.Mutex
+	queue   []func()
+	running bool
+}
+
+// push queues fn without waiting for it, fn may push again
+func (s *stopper) push(fn func()) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.queue = append(s.queue, fn)
+	if !s.running {
+		s.running = true
+		go s.loop()
+	}
+}
+
+func (s *stopper) loop() {
+	for {
+		s.mu.Lock()
+		queue := s.queue
+		s.queue = nil
+		if len(queue) == 0 {
+			s.running = false
+			s.mu.Unlock()
+			return
+		}
+		s.mu.Unlock()
+		for _, fn := range queue {
+			fn()
+		}
+	}
+}
@@ -0,0 +1,99 @@
+package eventbus
+
+import (
+	"sync"
+
+	"github.com/lockp111/go-cmap"
+)
+
+// Store interface, the map of the topics used by the bus. The callbacks of Upsert and RemoveCb are called
+// atomically with the change, the callback of IterCb must not change the store
+type Store[T any] interface {
+	Get(topic string) (*Topic[T], bool)
+	Upsert(topic string, cb cmap.UpsertCb[*Topic[T]]) *Topic[T]
+	RemoveCb(topic string, cb cmap.RemoveCb[string, *Topic[T]]) bool
+	IterCb(fn cmap.IterCb[string, *Topic[T]])
+	Count() int
+}
+
+// NewCMapStore - return the default Store, a sharded concurrent map which suits the heavy concurrent writes
+func NewCMapStore[T any]() Store[T] {
+	return cmap.New[*Topic[T]]()
+}
+
+// mapStore struct, a plain map guarded by a RWMutex
+type mapStore[T any] struct {
+	mu     sync.RWMutex
+	topics map[string]*Topic[T]
+}
+
+// NewMapStore - return a Store of a plain map guarded by a RWMutex, which suits the read heavy or single goroutine usage
+func NewMapStore[T any]() Store[T] {
+	return &mapStore[T]{topics: make(map[string]*Topic[T])}
+}
+
+func (s *mapStore[T]) Get(topic string) (*Topic[T], bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	t, ok := s.topics[topic]
+	return t, ok
+}
+
+func (s *mapStore[T]) Upsert(topic string, cb cmap.UpsertCb[*Topic[T]]) *Topic[T] {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	t, ok := s.topics[topic]
+	t = cb(t, ok)
+	s.topics[topic] = t
+	return t
+}
+
+func (s *mapStore[T]) RemoveCb(topic string, cb cmap.RemoveCb[string, *Topic[T]]) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	t, ok := s.topics[topic]
+	ok = cb(t, ok) && ok
+	if ok {
+		delete(s.topics, topic)
+	}
+	return ok
+}
+
+func (s *mapStore[T]) IterCb(fn cmap.IterCb[string, *Topic[T]]) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for topic, t := range s.topics {
+		fn(topic, t)
+	}
+}
+
+func (s *mapStore[T]) Count() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return len(s.topics)
+}
+
+// resolvedStore struct, it stores the topics by the keys returned by the resolver
+type resolvedStore[T any] struct {
+	Store[T]
+	resolve func(topic string) string
+}
+
+func (s *resolvedStore[T]) Get(topic string) (*Topic[T], bool) {
+	return s.Store.Get(s.resolve(topic))
+}
+
+func (s *resolvedStore[T]) Upsert(topic string, cb cmap.UpsertCb[*Topic[T]]) *Topic[T] {
+	return s.Store.Upsert(s.resolve(topic), cb)
+}
+
+func (s *resolvedStore[T]) RemoveCb(topic string, cb cmap.RemoveCb[string, *Topic[T]]) bool {
+	return s.Store.RemoveCb(s.resolve(topic), cb)
+}
+
+// IterCb passes the topic names instead of the resolved keys, so that they can be passed back to the store
+func (s *resolvedStore[T]) IterCb(fn cmap.IterCb[string, *Topic[T]]) {
+	s.Store.IterCb(func(_ string, t *Topic[T]) {
+		fn(t.name, t)
+	})
+}
@@ -0,0 +1,141 @@
+package eventbus
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+func TestMapStore(t *testing.T) {
+	o := New[string]().WithStore(NewMapStore[string]())
+	n := 0
+
+	o.On("foo", &N{&n, ""}, &N{&n, ""}).Once("bar", &N{&n, ""}).On(ALL, &N{&n, ""})
+	o.Trigger("foo").Trigger("bar").Trigger("bar")
+	if n != 6 {
+		t.Errorf("The counter is %d instead of being %d", n, 6)
+	}
+	if o.Exists("bar") {
+		t.Error("The topic bar should be removed")
+	}
+
+	o.Broadcast()
+	if n != 9 {
+		t.Errorf("The counter is %d instead of being %d", n, 9)
+	}
+	if c := o.CountSnapshot(); len(c) != 2 || c["foo"] != 2 || c[ALL] != 1 {
+		t.Errorf("The snapshot is %v instead of being %v", c, map[string]int{"foo": 2, ALL: 1})
+	}
+
+	o.CleanSync()
+	if c := o.topics.Count(); c != 0 {
+		t.Errorf("The topic count is %d instead of being %d", c, 0)
+	}
+}
+
+func TestWithTopicResolver(t *testing.T) {
+	o := New[string]().WithTopicResolver(func(topic string) string {
+		return "tenant/" + strings.ToLower(topic)
+	})
+	n := 0
+
+	onFoo := &N{&n, ""}
+	o.On("Foo", onFoo).On(ALL, &N{&n, ""}).Trigger("foo", "test")
+	if n != 2 {
+		t.Errorf("The counter is %d instead of being %d", n, 2)
+	}
+	if _, ok := o.topics.(*resolvedStore[string]).Store.Get("tenant/foo"); !ok {
+		t.Error("The topic should be stored by the resolved key")
+	}
+
+	o.Broadcast("test")
+	if n != 4 {
+		t.Errorf("The counter is %d instead of being %d", n, 4)
+	}
+	if c := o.CountSnapshot(); c["Foo"] != 1 || c[ALL] != 1 {
+		t.Errorf("The snapshot is %v instead of being %v", c, map[string]int{"Foo": 1, ALL: 1})
+	}
+
+	o.Off("FOO", onFoo).Trigger("foo", "test")
+	if n != 5 {
+		t.Errorf("The counter is %d instead of being %d", n, 5)
+	}
+	o.CleanSync()
+	if c := o.TopicCount(); c != 0 {
+		t.Errorf("The topic count is %d instead of being %d", c, 0)
+	}
+}
+
+func benchmarkStoreReadHeavy(b *testing.B, store Store[string]) {
+	bus := New[string]().WithStore(store)
+	var counter int64
+	for i := 0; i < 100; i++ {
+		bus.On(fmt.Sprintf("topic-%d", i), &benchmarkEvent{&counter})
+	}
+
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		i := 0
+		for pb.Next() {
+			topic := fmt.Sprintf("topic-%d", i%100)
+			if i%100 == 0 {
+				bus.Off(topic).On(topic, &benchmarkEvent{&counter})
+			} else {
+				bus.Trigger(topic, "test message")
+			}
+			i++
+		}
+	})
+	b.StopTimer()
+
+	b.ReportMetric(float64(atomic.LoadInt64(&counter)), "dispatches")
+}
+
+// 基准测试：默认的分片存储在读多写少时的性能
+func BenchmarkCMapStoreReadHeavy(b *testing.B) {
+	benchmarkStoreReadHeavy(b, NewCMapStore[string]())
+}
+
+// 基准测试：读写锁存储在读多写少时的性能
+func BenchmarkMapStoreReadHeavy(b *testing.B) {
+	benchmarkStoreReadHeavy(b, NewMapStore[string]())
+}
+
+// 基准测试：读写锁存储的单协程性能
+func BenchmarkMapStoreSingle(b *testing.B) {
+	bus := New[string]().WithStore(NewMapStore[string]())
+	var counter int64
+	bus.On("topic", &benchmarkEvent{&counter})
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		bus.Trigger("topic", "test message")
+	}
+}
+
+func TestMapStoreRace(t *testing.T) {
+	o := New[string]().WithStore(NewMapStore[string]())
+	var counter int64
+	var wg sync.WaitGroup
+
+	for i := 0; i < 100; i++ {
+		wg.Add(2)
+		go func(i int) {
+			defer wg.Done()
+			topic := fmt.Sprintf("topic-%d", i%10)
+			e := &benchmarkEvent{&counter}
+			o.On(topic, e).Off(topic, e)
+		}(i)
+		go func(i int) {
+			defer wg.Done()
+			o.Trigger(fmt.Sprintf("topic-%d", i%10), "test message")
+		}(i)
+	}
+	wg.Wait()
+
+	if c := o.topics.Count(); c != 0 {
+		t.Errorf("The topic count is %d instead of being %d", c, 0)
+	}
+}
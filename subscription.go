@@ -0,0 +1,57 @@
+package eventbus
+
+import (
+	"context"
+	"math"
+	"sync/atomic"
+	"time"
+)
+
+// Subscription struct, the handle of a registered event
+type Subscription[T any] struct {
+	bus   *Bus[T]
+	event *event[T]
+}
+
+func newSubscription[T any](b *Bus[T], e Event[T], topic string, isUnique bool) *Subscription[T] {
+	ev := newEvent(e, topic, isUnique)
+	ev.owned = true
+	return &Subscription[T]{b, ev}
+}
+
+// Unsubscribe - remove the event, OnStop is called if it is still registered
+func (s *Subscription[T]) Unsubscribe() {
+	s.bus.removeFunc(s.event.topicName(), func(e *event[T]) bool {
+		return e == s.event
+	})
+}
+
+// OnTTL - register topic event which is removed after ttl, OnStop is called after the removal
+func (b *Bus[T]) OnTTL(topic string, ttl time.Duration, e Event[T]) *Subscription[T] {
+	s := newSubscription(b, e, topic, false)
+	// the timer is armed after the event is inserted, so that it never expires before the insertion
+	s.event.timer = time.AfterFunc(math.MaxInt64, s.Unsubscribe)
+	if _, err := b.insertEvents(topic, []*event[T]{s.event}); err == nil {
+		s.event.timer.Reset(ttl)
+	}
+	return s
+}
+
+// OnceCtx - register once event which is removed if ctx is done before it is called, OnStop is called after the removal.
+// The event is either called or removed by ctx, never both. Its guard is not shared with the other once events of the handler
+func (b *Bus[T]) OnceCtx(ctx context.Context, topic string, e Event[T]) *Subscription[T] {
+	s := newSubscription(b, e, topic, true)
+	s.event.guard = &onceGuard[T]{events: []*event[T]{s.event}}
+	inserted := make(chan struct{})
+	s.event.unwatch = context.AfterFunc(ctx, func() {
+		// a done ctx never removes the event before it is inserted
+		<-inserted
+		// the guard is claimed first, so that the event is never called after the cancellation
+		if atomic.CompareAndSwapUint32(&s.event.guard.hasCalled, 0, 1) {
+			s.Unsubscribe()
+		}
+	})
+	b.insertEvents(topic, []*event[T]{s.event})
+	close(inserted)
+	return s
+}
@@ -0,0 +1,132 @@
+package eventbus
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+type ttlEvent struct {
+	n       int
+	stopped chan string
+}
+
+func (e *ttlEvent) Dispatch(topic string, data ...string) {
+	e.n++
+}
+
+func (e *ttlEvent) OnStop(topic string) {
+	e.stopped <- topic
+}
+
+func TestOnTTL(t *testing.T) {
+	o := New[string]()
+
+	onFoo := &ttlEvent{stopped: make(chan string, 1)}
+	o.OnTTL("foo", 20*time.Millisecond, onFoo)
+
+	o.Trigger("foo", "1")
+	if onFoo.n != 1 {
+		t.Errorf("The counter is %d instead of being %d", onFoo.n, 1)
+	}
+
+	select {
+	case topic := <-onFoo.stopped:
+		if topic != "foo" {
+			t.Errorf("The stopped topic is %s instead of being %s", topic, "foo")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("The event is not removed after ttl")
+	}
+
+	o.Trigger("foo", "2")
+	if onFoo.n != 1 {
+		t.Errorf("The counter is %d instead of being %d", onFoo.n, 1)
+	}
+	if c := o.EventCount("foo"); c != 0 {
+		t.Errorf("The event count is %d instead of being %d", c, 0)
+	}
+}
+
+func TestOnTTLOff(t *testing.T) {
+	o := New[string]()
+
+	onFoo := &ttlEvent{stopped: make(chan string, 2)}
+	s := o.OnTTL("foo", time.Hour, onFoo)
+
+	o.Off("foo", onFoo)
+	<-onFoo.stopped
+	if s.event.timer.Stop() {
+		t.Error("The timer should be stopped by Off")
+	}
+}
+
+func TestOnceCtx(t *testing.T) {
+	o := New[string]()
+
+	// fired before the cancellation
+	ctx, cancel := context.WithCancel(context.Background())
+	fired := &ttlEvent{stopped: make(chan string, 2)}
+	o.OnceCtx(ctx, "foo", fired)
+	o.Trigger("foo").Trigger("foo")
+	cancel()
+	if fired.n != 1 {
+		t.Errorf("The counter is %d instead of being %d", fired.n, 1)
+	}
+	select {
+	case <-fired.stopped:
+	case <-time.After(time.Second):
+		t.Fatal("The called event is not stopped")
+	}
+	select {
+	case <-fired.stopped:
+		t.Error("The event is stopped twice")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	// cancelled before being fired
+	ctx, cancel = context.WithCancel(context.Background())
+	canceled := &ttlEvent{stopped: make(chan string, 2)}
+	o.OnceCtx(ctx, "foo", canceled)
+	cancel()
+	select {
+	case topic := <-canceled.stopped:
+		if topic != "foo" {
+			t.Errorf("The stopped topic is %s instead of being %s", topic, "foo")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("The event is not removed after the cancellation")
+	}
+	o.Trigger("foo")
+	if canceled.n != 0 {
+		t.Errorf("The counter is %d instead of being %d", canceled.n, 0)
+	}
+	if c := o.EventCount("foo"); c != 0 {
+		t.Errorf("The event count is %d instead of being %d", c, 0)
+	}
+
+	// the done ctx removes the event at once
+	o.OnceCtx(ctx, "bar", canceled)
+	<-canceled.stopped
+	if c := o.EventCount("bar"); c != 0 {
+		t.Errorf("The event count is %d instead of being %d", c, 0)
+	}
+}
+
+func TestOnceCtxRace(t *testing.T) {
+	o := New[string]()
+
+	for i := 0; i < 100; i++ {
+		ctx, cancel := context.WithCancel(context.Background())
+		e := &ttlEvent{stopped: make(chan string, 2)}
+		o.OnceCtx(ctx, "foo", e)
+		go cancel()
+		o.Trigger("foo")
+		<-e.stopped
+		select {
+		case <-e.stopped:
+			t.Fatal("The event is stopped twice")
+		case <-time.After(time.Millisecond):
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package eventbus
+
+import (
+	"slices"
+	"sync"
+	"time"
+)
+
+// latencySamples is the number of the last dispatch durations kept for each topic
+const latencySamples = 1024
+
+// latency struct, a ring buffer of the last dispatch durations of a topic
+type latency struct {
+	mu      sync.Mutex
+	next    int
+	samples []time.Duration
+}
+
+func (l *latency) record(d time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if len(l.samples) < latencySamples {
+		l.samples = append(l.samples, d)
+		return
+	}
+	l.samples[l.next] = d
+	l.next = (l.next + 1) % latencySamples
+}
+
+// percentiles returns the p50, p95 and p99 of the samples
+func (l *latency) percentiles() (p50, p95, p99 time.Duration) {
+	l.mu.Lock()
+	samples := slices.Clone(l.samples)
+	l.mu.Unlock()
+	if len(samples) == 0 {
+		return
+	}
+	slices.Sort(samples)
+	at := func(p int) time.Duration {
+		return samples[(len(samples)-1)*p/100]
+	}
+	return at(50), at(95), at(99)
+}
+
+// EnableTiming - record the dispatch duration of every topic, it is off by default
+func (b *Bus[T]) EnableTiming() *Bus[T] {
+	b.timing = true
+	return b
+}
+
+// TopicLatency - return the percentiles of the last dispatch durations of the topic,
+// they are zero if the timing is not enabled or the topic has not been dispatched
+func (b *Bus[T]) TopicLatency(topic string) (p50, p95, p99 time.Duration) {
+	if l, ok := b.latencies.get(topic); ok {
+		return l.percentiles()
+	}
+	return
+}
+
+// recordLatency records the dispatch duration of the topic
+func (b *Bus[T]) recordLatency(topic string, d time.Duration) {
+	b.latencies.store().Upsert(topic, func(l *latency, exist bool) *latency {
+		if !exist {
+			l = &latency{}
+		}
+		return l
+	}).record(d)
+}
@@ -0,0 +1,50 @@
+package eventbus
+
+import (
+	"testing"
+	"time"
+)
+
+type scheduleEvent struct {
+	sleeps []time.Duration
+}
+
+func (e *scheduleEvent) Dispatch(topic string, data ...string) {
+	time.Sleep(e.sleeps[0])
+	e.sleeps = e.sleeps[1:]
+}
+
+func TestTopicLatency(t *testing.T) {
+	o := New[string]()
+
+	e := &scheduleEvent{}
+	for i := 0; i < 100; i++ {
+		if i%10 == 0 {
+			e.sleeps = append(e.sleeps, 20*time.Millisecond)
+		} else {
+			e.sleeps = append(e.sleeps, time.Millisecond)
+		}
+	}
+	o.On("foo", e).Trigger("foo")
+	if p50, p95, p99 := o.TopicLatency("foo"); p50 != 0 || p95 != 0 || p99 != 0 {
+		t.Errorf("The latency is %s %s %s instead of being zero when the timing is disabled", p50, p95, p99)
+	}
+
+	o.EnableTiming()
+	for i := 1; i < 100; i++ {
+		o.Trigger("foo")
+	}
+	p50, p95, p99 := o.TopicLatency("foo")
+	if p50 < time.Millisecond || p50 >= 10*time.Millisecond {
+		t.Errorf("The p50 is %s instead of being about %s", p50, time.Millisecond)
+	}
+	if p95 < 20*time.Millisecond || p95 >= 100*time.Millisecond {
+		t.Errorf("The p95 is %s instead of being about %s", p95, 20*time.Millisecond)
+	}
+	if p99 < p95 {
+		t.Errorf("The p99 %s is less than the p95 %s", p99, p95)
+	}
+	if p50, _, _ := o.TopicLatency("bar"); p50 != 0 {
+		t.Errorf("The p50 is %s instead of being zero", p50)
+	}
+}
@@ -0,0 +1,169 @@
+package eventbus
+
+import (
+	"slices"
+	"sync"
+	"sync/atomic"
+)
+
+// Topic struct
+type Topic[T any] struct {
+	bus      *Bus[T]
+	name     string
+	mu       sync.RWMutex
+	events   []*event[T]
+	cursors  map[string]*atomic.Uint64
+	capacity int
+}
+
+func newTopic[T any](bus *Bus[T], name string) *Topic[T] {
+	capacity, _ := bus.capacities.get(name)
+	return &Topic[T]{
+		bus:      bus,
+		name:     name,
+		capacity: capacity,
+	}
+}
+
+// current returns the topic stored by the bus under the name, the topic held by the caller may have been
+// removed once it was empty, or never stored if it was got before the first subscription
+func (t *Topic[T]) current() *Topic[T] {
+	return t.bus.Get(t.name)
+}
+
+// Dispatch - dispatch msg to the topic events, and to the ALL events when asterisk is allowed
+func (t *Topic[T]) Dispatch(msg ...T) {
+	t.bus.dispatch(t.current(), newMessage(t.name, msg))
+}
+
+// DispatchLocal - dispatch msg to the topic events only, the ALL events are skipped like TriggerDirect
+func (t *Topic[T]) DispatchLocal(msg ...T) {
+	m := newMessage(t.name, msg)
+	m.direct = true
+	t.bus.dispatch(t.current(), m)
+}
+
+// Name - return the name of the topic
+func (t *Topic[T]) Name() string {
+	return t.name
+}
+
+// IsEmpty - return whether no event is subscribed to the topic
+func (t *Topic[T]) IsEmpty() bool {
+	return t.Count() == 0
+}
+
+// HasOnce - return whether a once event which has not been called is subscribed to the topic
+func (t *Topic[T]) HasOnce() bool {
+	for _, e := range t.current().snapshot() {
+		if e.isUnique && atomic.LoadUint32(&e.guard.hasCalled) == 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// Drain - remove all the topic events without calling OnStop and return them
+func (t *Topic[T]) Drain() []Event[T] {
+	return t.bus.Drain(t.name)
+}
+
+// Clear - remove all the topic events, OnStop of the removed events is called
+func (t *Topic[T]) Clear() {
+	t.bus.Off(t.name)
+}
+
+// Count - return the number of events subscribed to the topic
+func (t *Topic[T]) Count() int {
+	return t.current().count()
+}
+
+// count returns the number of the events of this topic object
+func (t *Topic[T]) count() int {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return len(t.events)
+}
+
+// snapshot returns the current events, the returned slice must not be modified.
+// Adding or removing events replaces the slice, so a dispatch loop over a snapshot
+// is never affected by the handlers subscribing or unsubscribing inside Dispatch
+func (t *Topic[T]) snapshot() []*event[T] {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.events
+}
+
+// setCapacity sets the capacity hint of the events slice
+func (t *Topic[T]) setCapacity(capacity int) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.capacity = capacity
+}
+
+// addEvents adds events after the events of a higher or the same priority, the slice is copied so that snapshots
+// stay untouched. The events are appended in place if they go last and the capacity is enough, the snapshots
+// never see them since they are beyond their length
+func (t *Topic[T]) addEvents(es ...*event[T]) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if len(t.events)+len(es) <= cap(t.events) && appendable(t.events, es) {
+		t.events = append(t.events, es...)
+		return
+	}
+	events := make([]*event[T], 0, max(len(t.events)+len(es), t.capacity))
+	events = append(events, t.events...)
+	for _, e := range es {
+		i := slices.IndexFunc(events, func(v *event[T]) bool {
+			return v.priority < e.priority
+		})
+		if i < 0 {
+			events = append(events, e)
+		} else {
+			events = slices.Insert(events, i, e)
+		}
+	}
+	t.events = events
+}
+
+// replaceEvents replaces the events for which fn returns another event, the slice is copied so that snapshots stay untouched
+func (t *Topic[T]) replaceEvents(fn func(e *event[T]) *event[T]) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	var events []*event[T]
+	for i, e := range t.events {
+		r := fn(e)
+		if r == nil {
+			continue
+		}
+		if events == nil {
+			events = make([]*event[T], len(t.events), max(len(t.events), t.capacity))
+			copy(events, t.events)
+		}
+		events[i] = r
+	}
+	if events != nil {
+		t.events = events
+	}
+}
+
+// removeEvents removes the events which match fn and returns them
+func (t *Topic[T]) removeEvents(fn func(e *event[T]) bool) []*event[T] {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	var (
+		events  = make([]*event[T], 0, max(len(t.events), t.capacity))
+		removed []*event[T]
+	)
+	for _, e := range t.events {
+		if fn(e) {
+			removed = append(removed, e)
+			continue
+		}
+		events = append(events, e)
+	}
+	if len(removed) > 0 {
+		t.events = events
+	}
+	return removed
+}
@@ -0,0 +1,52 @@
+package eventbus
+
+// Typed struct, a Bus whose topics are a string kind type, so that the topics are checked at compile time
+type Typed[K ~string, T any] struct {
+	bus *Bus[T]
+}
+
+// NewTyped - return a new Typed object
+func NewTyped[K ~string, T any]() *Typed[K, T] {
+	return &Typed[K, T]{
+		bus: New[T](),
+	}
+}
+
+// Bus - return the underlying bus
+func (b *Typed[K, T]) Bus() *Bus[T] {
+	return b.bus
+}
+
+// On - register topic event
+func (b *Typed[K, T]) On(topic K, e ...Event[T]) *Typed[K, T] {
+	b.bus.On(string(topic), e...)
+	return b
+}
+
+// Once - register once event
+func (b *Typed[K, T]) Once(topic K, e ...Event[T]) *Typed[K, T] {
+	b.bus.Once(string(topic), e...)
+	return b
+}
+
+// Off - remove topic event
+func (b *Typed[K, T]) Off(topic K, e ...Event[T]) *Typed[K, T] {
+	b.bus.Off(string(topic), e...)
+	return b
+}
+
+// Emit - dispatch event
+func (b *Typed[K, T]) Emit(topic K, msg ...T) *Typed[K, T] {
+	b.bus.Emit(string(topic), msg...)
+	return b
+}
+
+// Trigger - dispatch event, same as Emit
+func (b *Typed[K, T]) Trigger(topic K, msg ...T) *Typed[K, T] {
+	return b.Emit(topic, msg...)
+}
+
+// Get - return the topic
+func (b *Typed[K, T]) Get(topic K) *Topic[T] {
+	return b.bus.Get(string(topic))
+}
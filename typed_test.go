@@ -0,0 +1,34 @@
+package eventbus
+
+import (
+	"testing"
+)
+
+type orderTopic string
+
+const (
+	orderCreated orderTopic = "order.created"
+	orderPaid    orderTopic = "order.paid"
+)
+
+func TestTyped(t *testing.T) {
+	o := NewTyped[orderTopic, string]()
+	n := 0
+
+	onCreated := &N{&n, ""}
+	onPaid := &N{&n, ""}
+	o.On(orderCreated, onCreated).On(orderPaid, onPaid)
+
+	o.Trigger(orderCreated, "1").Trigger(orderPaid, "2")
+	if n != 2 {
+		t.Errorf("The counter is %d instead of being %d", n, 2)
+	}
+	if onCreated.s != "1" || onPaid.s != "2" {
+		t.Errorf("The messages are %s and %s instead of being %s and %s", onCreated.s, onPaid.s, "1", "2")
+	}
+
+	o.Off(orderPaid, onPaid).Trigger(orderPaid, "3")
+	if onPaid.s != "2" {
+		t.Errorf("The last event name triggered is %s instead of being %s", onPaid.s, "2")
+	}
+}
@@ -0,0 +1,53 @@
+package eventbus
+
+import (
+	"context"
+	"runtime"
+	"weak"
+)
+
+// weakEvent struct, it holds a weak pointer to the handler, so that the bus never keeps it alive
+type weakEvent[T, E any, P interface {
+	*E
+	Event[T]
+}] struct {
+	bus *Bus[T]
+	p   weak.Pointer[E]
+	sub *Subscription[T]
+}
+
+func (w *weakEvent[T, E, P]) Dispatch(topic string, data ...T) {
+	w.relay(newMessage(topic, data), data)
+}
+
+func (w *weakEvent[T, E, P]) relay(m *message[T], data []T) {
+	e := P(w.p.Value())
+	if e == nil {
+		// the handler is collected, the subscription is pruned after the current dispatch
+		w.sub.Unsubscribe()
+		return
+	}
+	w.bus.deliver(e, m, data)
+}
+
+func (w *weakEvent[T, E, P]) OnStopCtx(ctx context.Context, topic string) {
+	if e := P(w.p.Value()); e != nil {
+		callStop(ctx, e, topic)
+	}
+}
+
+// OnWeak - register the topic event by a weak pointer, the event is removed automatically after it is
+// garbage collected. OnStop is called only if the event is removed while it is still alive.
+// It returns the Subscription which removes the event, Off can't find it since the bus doesn't hold the event
+func OnWeak[T, E any, P interface {
+	*E
+	Event[T]
+}](b *Bus[T], topic string, e P) *Subscription[T] {
+	w := &weakEvent[T, E, P]{bus: b, p: weak.Make((*E)(e))}
+	w.sub = newSubscription[T](b, w, topic, false)
+	b.insertEvents(topic, []*event[T]{w.sub.event})
+	runtime.AddCleanup((*E)(e), func(s *Subscription[T]) {
+		s.Unsubscribe()
+	}, w.sub)
+	return w.sub
+}
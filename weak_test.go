@@ -0,0 +1,42 @@
+package eventbus
+
+import (
+	"runtime"
+	"testing"
+	"time"
+)
+
+func TestOnWeak(t *testing.T) {
+	o := New[string]()
+	n := 0
+
+	e := &N{&n, ""}
+	s := OnWeak(o, "foo", e)
+	o.Trigger("foo")
+	if n != 1 {
+		t.Errorf("The counter is %d instead of being %d", n, 1)
+	}
+	s.Unsubscribe()
+	if c := o.EventCount("foo"); c != 0 {
+		t.Errorf("The event count is %d instead of being %d", c, 0)
+	}
+	runtime.KeepAlive(e)
+
+	// the event is removed after the last strong reference is dropped
+	OnWeak(o, "foo", &N{&n, ""})
+	if c := o.EventCount("foo"); c != 1 {
+		t.Errorf("The event count is %d instead of being %d", c, 1)
+	}
+	deadline := time.Now().Add(time.Second)
+	for o.EventCount("foo") != 0 && time.Now().Before(deadline) {
+		runtime.GC()
+		time.Sleep(time.Millisecond)
+	}
+	if c := o.EventCount("foo"); c != 0 {
+		t.Errorf("The event count is %d instead of being %d", c, 0)
+	}
+	o.Trigger("foo")
+	if n != 1 {
+		t.Errorf("The counter is %d instead of being %d", n, 1)
+	}
+}